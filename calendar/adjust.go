@@ -0,0 +1,144 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package calendar
+
+import "time"
+
+/*
+=======================
+Business-Day Convention
+=======================
+*/
+
+/*
+BusinessDayConvention represents the rule that rolls a date that is not a
+business day to a business day.
+*/
+type BusinessDayConvention int
+
+/*
+The business-day conventions:
+Unadjusted        the date is not rolled;
+Following         the date is rolled to the next business day;
+ModifiedFollowing the date is rolled to the next business day, unless it
+                  falls in the next month, in which case it is rolled to
+                  the previous business day;
+Preceding         the date is rolled to the previous business day.
+*/
+const (
+	Unadjusted BusinessDayConvention = iota
+	Following
+	ModifiedFollowing
+	Preceding
+)
+
+/*
+Adjust returns the date t rolled to a business day of the calendar with
+the business-day convention bdc. A date that is already a business day is
+returned unchanged.
+
+Usage (example):
+var d = c.Adjust(t, calendar.ModifiedFollowing)
+*/
+func (c *Calendar) Adjust(t time.Time, bdc BusinessDayConvention) time.Time {
+	switch bdc {
+	case Following:
+		return c.roll(t, 1)
+	case ModifiedFollowing:
+		if d := c.roll(t, 1); d.Month() == t.Month() {
+			return d
+		}
+		return c.roll(t, -1)
+	case Preceding:
+		return c.roll(t, -1)
+	}
+	return t
+}
+
+/*
+roll is an unexported method that steps the date t by step days until it
+is a business day of the calendar.
+*/
+func (c *Calendar) roll(t time.Time, step int) time.Time {
+	for !c.IsBusinessDay(t) {
+		t = t.AddDate(0, 0, step)
+	}
+	return t
+}
+
+/*
+AddBusinessDays returns the date n business days of the calendar after
+the date t (or before it, if n is negative). If t is not a business day,
+it is first rolled to the next business day when n is positive, or to the
+previous business day when n is negative.
+
+Usage (example):
+var d = c.AddBusinessDays(t, 2)
+*/
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	t = c.roll(t, step)
+	for i := 0; i < n; i++ {
+		t = c.roll(t.AddDate(0, 0, step), step)
+	}
+	return t
+}
+
+/*
+AddMonths returns the date n months after the date t (or before it, if n
+is negative), rolled to a business day of the calendar with the
+business-day convention bdc. A day that does not exist in the target month
+is clamped to the last day of that month. If eom is true and t is the last
+business day of its month, the result is the last business day of the
+target month (the end-of-month rule).
+
+Usage (example):
+var d = c.AddMonths(t, 3, calendar.ModifiedFollowing, true)
+*/
+func (c *Calendar) AddMonths(t time.Time, n int, bdc BusinessDayConvention, eom bool) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1)
+	if eom && c.isLastBusinessDayOfMonth(t) {
+		return c.roll(last, -1)
+	}
+	if d > last.Day() {
+		return c.Adjust(last, bdc)
+	}
+	return c.Adjust(first.AddDate(0, 0, d-1), bdc)
+}
+
+/*
+isLastBusinessDayOfMonth is an unexported method that returns true if the
+date t is the last business day of its month.
+*/
+func (c *Calendar) isLastBusinessDayOfMonth(t time.Time) bool {
+	return c.IsBusinessDay(t) && c.roll(t.AddDate(0, 0, 1), 1).Month() != t.Month()
+}
@@ -0,0 +1,223 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+/*
+Package calendar provides the holiday calendars and the business-day
+conventions that are used to roll dates that fall on non-business days.
+
+This is a multi-file package and is made up of the following source files:
+  calendar.go provides the holiday calendars and the joint calendars;
+  adjust.go   provides the business-day conventions and the date rolling;
+  load.go     provides the loading of holiday calendars from CSV and JSON
+              files.
+*/
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidCalendar is returned when the data of a holiday
+calendar cannot be read or parsed, or its weekend is invalid.
+*/
+type ErrInvalidCalendar string
+
+func (e ErrInvalidCalendar) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+================
+Holiday Calendar
+================
+*/
+
+/*
+Calendar represents a holiday calendar, made up of the days of the week
+that are weekend days and a set of holidays. It is created by
+NewCalendar, Joint, LoadCSV or LoadJSON. At least one day of the week
+must not be a weekend day.
+
+A Calendar can be passed wherever the daycount package expects a
+daycount.BusinessDays, e.g. to the daycount.Business252 convention.
+*/
+type Calendar struct {
+	Name     string
+	weekend  [7]bool
+	holidays map[int]bool
+}
+
+/*
+Weekend is the weekend of Saturday and Sunday.
+*/
+var Weekend = []time.Weekday{time.Saturday, time.Sunday}
+
+/*
+NewCalendar creates a calendar with the given name, weekend days and
+holidays. It returns the error ErrInvalidCalendar if a weekend day is not
+a day of the week, or if all seven days of the week are weekend days.
+
+Usage (example):
+var c, e = calendar.NewCalendar("XYZ", calendar.Weekend, []time.Time{
+	time.Date(2016, 12, 26, 0, 0, 0, 0, time.UTC),
+})
+*/
+func NewCalendar(name string, weekend []time.Weekday, holidays []time.Time) (*Calendar, error) {
+	c := &Calendar{Name: name, holidays: make(map[int]bool)}
+	for _, d := range weekend {
+		if d < time.Sunday || d > time.Saturday {
+			return nil, ErrInvalidCalendar(fmt.Sprintf("The weekend day %d is not a day of the week.", int(d)))
+		}
+		c.weekend[d] = true
+	}
+	if err := c.checkWeekend(); err != nil {
+		return nil, err
+	}
+	for _, t := range holidays {
+		c.AddHoliday(t)
+	}
+	return c, nil
+}
+
+/*
+Joint creates a calendar that joins the given calendars, i.e. a day is a
+business day of the joint calendar only if it is a business day of all of
+the given calendars. The joint calendar is named after the names of the
+given calendars, joined by '+'. It returns the error ErrInvalidCalendar
+if all seven days of the week are weekend days of the joint calendar.
+
+Usage (example):
+var c, e = calendar.Joint(c1, c2)
+*/
+func Joint(cals ...*Calendar) (*Calendar, error) {
+	c := &Calendar{holidays: make(map[int]bool)}
+	for i, cal := range cals {
+		if i > 0 {
+			c.Name += "+"
+		}
+		c.Name += cal.Name
+		for d, w := range cal.weekend {
+			c.weekend[d] = c.weekend[d] || w
+		}
+		for k := range cal.holidays {
+			c.holidays[k] = true
+		}
+	}
+	if err := c.checkWeekend(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+/*
+checkWeekend is an unexported method that returns the error
+ErrInvalidCalendar if all seven days of the week are weekend days of the
+calendar, which would leave no business day to roll a date to.
+*/
+func (c *Calendar) checkWeekend() error {
+	for _, w := range c.weekend {
+		if !w {
+			return nil
+		}
+	}
+	return ErrInvalidCalendar(fmt.Sprintf("The calendar %q has no business days of the week.", c.Name))
+}
+
+/*
+key is an unexported function that returns the calendar date of t as an
+integer of the form yyyymmdd.
+*/
+func key(t time.Time) int {
+	y, m, d := t.Date()
+	return y*10000 + int(m)*100 + d
+}
+
+/*
+AddHoliday adds the calendar date of t to the holidays of the calendar.
+*/
+func (c *Calendar) AddHoliday(t time.Time) {
+	if c.holidays == nil {
+		c.holidays = make(map[int]bool)
+	}
+	c.holidays[key(t)] = true
+}
+
+/*
+RemoveHoliday removes the calendar date of t from the holidays of the
+calendar.
+*/
+func (c *Calendar) RemoveHoliday(t time.Time) {
+	delete(c.holidays, key(t))
+}
+
+/*
+Holidays returns the holidays of the calendar, in ascending order, as
+dates at midnight UTC.
+*/
+func (c *Calendar) Holidays() []time.Time {
+	keys := make([]int, 0, len(c.holidays))
+	for k := range c.holidays {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	days := make([]time.Time, len(keys))
+	for i, k := range keys {
+		days[i] = time.Date(k/10000, time.Month(k/100%100), k%100, 0, 0, 0, 0, time.UTC)
+	}
+	return days
+}
+
+/*
+IsWeekend returns true if the date t falls on a weekend day of the
+calendar.
+*/
+func (c *Calendar) IsWeekend(t time.Time) bool {
+	return c.weekend[t.Weekday()]
+}
+
+/*
+IsHoliday returns true if the date t is a holiday of the calendar.
+*/
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	return c.holidays[key(t)]
+}
+
+/*
+IsBusinessDay returns true if the date t is neither a weekend day nor a
+holiday of the calendar.
+*/
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	return !c.IsWeekend(t) && !c.IsHoliday(t)
+}
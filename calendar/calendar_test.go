@@ -0,0 +1,241 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var allWeek = []time.Weekday{
+	time.Sunday, time.Monday, time.Tuesday, time.Wednesday,
+	time.Thursday, time.Friday, time.Saturday,
+}
+
+func TestNewCalendarRejectsAllWeekend(t *testing.T) {
+	if _, err := NewCalendar("XYZ", allWeek, nil); err == nil {
+		t.Error("NewCalendar with a seven-day weekend returned no error")
+	}
+	if _, err := NewCalendar("XYZ", []time.Weekday{time.Weekday(7)}, nil); err == nil {
+		t.Error("NewCalendar with an invalid weekday returned no error")
+	}
+}
+
+func TestJointRejectsAllWeekend(t *testing.T) {
+	c1, err := NewCalendar("ABC", allWeek[:4], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := NewCalendar("XYZ", allWeek[4:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Joint(c1, c2); err == nil {
+		t.Error("Joint of calendars that cover the whole week returned no error")
+	}
+	if _, err := Joint(c1, c1); err != nil {
+		t.Errorf("Joint returned %v", err)
+	}
+}
+
+func TestLoadJSONRejectsAllWeekend(t *testing.T) {
+	r := strings.NewReader(`{"name": "XYZ", "weekend": ["Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"]}`)
+	if _, err := LoadJSON(r); err == nil {
+		t.Error("LoadJSON with a seven-day weekend returned no error")
+	}
+}
+
+func TestAddHolidayZeroValue(t *testing.T) {
+	var c Calendar
+	d := time.Date(2016, 12, 26, 0, 0, 0, 0, time.UTC)
+	c.AddHoliday(d)
+	if !c.IsHoliday(d) || c.IsBusinessDay(d) {
+		t.Error("AddHoliday on a zero-value Calendar did not add the holiday")
+	}
+	if got := c.Adjust(d, Following); !got.Equal(d.AddDate(0, 0, 1)) {
+		t.Errorf("Adjust(%v, Following) = %v", d, got)
+	}
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+/*
+testCalendar returns a calendar with a Saturday and Sunday weekend and
+holidays on Monday 26 December 2016, Friday 30 December 2016 and Monday
+2 January 2017.
+*/
+func testCalendar(t *testing.T) *Calendar {
+	c, err := NewCalendar("XYZ", Weekend, []time.Time{
+		date(2016, 12, 26), date(2016, 12, 30), date(2017, 1, 2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestAdjust(t *testing.T) {
+	c := testCalendar(t)
+	tests := []struct {
+		d    time.Time
+		bdc  BusinessDayConvention
+		want time.Time
+	}{
+		{date(2016, 12, 24), Unadjusted, date(2016, 12, 24)},
+		{date(2016, 12, 23), Following, date(2016, 12, 23)},
+		{date(2016, 12, 24), Following, date(2016, 12, 27)},
+		{date(2016, 12, 31), Following, date(2017, 1, 3)},
+		{date(2016, 12, 24), ModifiedFollowing, date(2016, 12, 27)},
+		{date(2016, 12, 31), ModifiedFollowing, date(2016, 12, 29)},
+		{date(2016, 12, 26), Preceding, date(2016, 12, 23)},
+		{date(2017, 1, 2), Preceding, date(2016, 12, 29)},
+	}
+	for _, tc := range tests {
+		if got := c.Adjust(tc.d, tc.bdc); !got.Equal(tc.want) {
+			t.Errorf("Adjust(%v, %v) = %v, want %v", tc.d, tc.bdc, got, tc.want)
+		}
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	c := testCalendar(t)
+	tests := []struct {
+		d    time.Time
+		n    int
+		bdc  BusinessDayConvention
+		eom  bool
+		want time.Time
+	}{
+		{date(2016, 1, 31), 1, Unadjusted, false, date(2016, 2, 29)},
+		{date(2015, 1, 31), 1, Unadjusted, false, date(2015, 2, 28)},
+		{date(2015, 1, 31), 1, Following, false, date(2015, 3, 2)},
+		{date(2015, 1, 31), 1, ModifiedFollowing, false, date(2015, 2, 27)},
+		{date(2016, 2, 29), 1, Following, false, date(2016, 3, 29)},
+		{date(2016, 2, 29), 1, Following, true, date(2016, 3, 31)},
+		{date(2016, 1, 29), 2, Following, true, date(2016, 3, 31)},
+		{date(2016, 1, 28), 2, Following, true, date(2016, 3, 28)},
+		{date(2016, 11, 30), 1, Following, false, date(2017, 1, 3)},
+		{date(2016, 11, 30), 1, ModifiedFollowing, false, date(2016, 12, 29)},
+		{date(2016, 11, 30), 1, Following, true, date(2016, 12, 29)},
+		{date(2016, 12, 29), -1, Following, true, date(2016, 11, 30)},
+	}
+	for _, tc := range tests {
+		if got := c.AddMonths(tc.d, tc.n, tc.bdc, tc.eom); !got.Equal(tc.want) {
+			t.Errorf("AddMonths(%v, %d, %v, %v) = %v, want %v", tc.d, tc.n, tc.bdc, tc.eom, got, tc.want)
+		}
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	c := testCalendar(t)
+	tests := []struct {
+		d    time.Time
+		n    int
+		want time.Time
+	}{
+		{date(2016, 12, 23), 0, date(2016, 12, 23)},
+		{date(2016, 12, 24), 0, date(2016, 12, 27)},
+		{date(2016, 12, 23), 1, date(2016, 12, 27)},
+		{date(2016, 12, 23), 3, date(2016, 12, 29)},
+		{date(2016, 12, 23), 4, date(2017, 1, 3)},
+		{date(2016, 12, 27), -1, date(2016, 12, 23)},
+		{date(2017, 1, 3), -2, date(2016, 12, 28)},
+		{date(2017, 1, 1), -1, date(2016, 12, 28)},
+	}
+	for _, tc := range tests {
+		if got := c.AddBusinessDays(tc.d, tc.n); !got.Equal(tc.want) {
+			t.Errorf("AddBusinessDays(%v, %d) = %v, want %v", tc.d, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestLoadCSV(t *testing.T) {
+	r := strings.NewReader("date,name\n2016-12-25,Christmas Day\n# observed\n\n2016-12-26, Boxing Day\n")
+	c, err := LoadCSV("XYZ", r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{date(2016, 12, 25), date(2016, 12, 26)}
+	got := c.Holidays()
+	if len(got) != len(want) {
+		t.Fatalf("Holidays() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("Holidays()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if c.Name != "XYZ" || !c.IsWeekend(date(2016, 12, 24)) || c.IsWeekend(date(2016, 12, 23)) {
+		t.Error("LoadCSV did not create a XYZ calendar with a Saturday and Sunday weekend")
+	}
+	for _, s := range []string{"2016-12-32\n", "25/12/2016,Christmas Day\n", "date,name\ndate,name\n"} {
+		if _, err := LoadCSV("XYZ", strings.NewReader(s)); err == nil {
+			t.Errorf("LoadCSV(%q) returned no error", s)
+		} else if _, ok := err.(ErrInvalidCalendar); !ok {
+			t.Errorf("LoadCSV(%q) returned %T, want ErrInvalidCalendar", s, err)
+		}
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	r := strings.NewReader(`{"name": "XYZ", "weekend": ["friday", "Saturday"], "holidays": ["2016-12-25", "2016-12-26"]}`)
+	c, err := LoadJSON(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "XYZ" {
+		t.Errorf("Name = %q, want XYZ", c.Name)
+	}
+	if !c.IsWeekend(date(2016, 12, 23)) || !c.IsWeekend(date(2016, 12, 24)) || c.IsWeekend(date(2016, 12, 25)) {
+		t.Error("LoadJSON did not create a Friday and Saturday weekend")
+	}
+	if !c.IsHoliday(date(2016, 12, 25)) || !c.IsHoliday(date(2016, 12, 26)) || len(c.Holidays()) != 2 {
+		t.Errorf("Holidays() = %v", c.Holidays())
+	}
+	c, err = LoadJSON(strings.NewReader(`{"name": "XYZ", "holidays": ["2016-12-26"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.IsWeekend(date(2016, 12, 24)) || !c.IsWeekend(date(2016, 12, 25)) || c.IsWeekend(date(2016, 12, 23)) {
+		t.Error("LoadJSON without a weekend did not assume a Saturday and Sunday weekend")
+	}
+	for _, s := range []string{
+		`{"name": "XYZ", "weekend": ["Caturday"]}`,
+		`{"name": "XYZ", "holidays": ["26/12/2016"]}`,
+		`{"name": "XYZ", "holidays": [20161226]}`,
+		`{"name": "XYZ"`,
+	} {
+		if _, err := LoadJSON(strings.NewReader(s)); err == nil {
+			t.Errorf("LoadJSON(%q) returned no error", s)
+		} else if _, ok := err.(ErrInvalidCalendar); !ok {
+			t.Errorf("LoadJSON(%q) returned %T, want ErrInvalidCalendar", s, err)
+		}
+	}
+}
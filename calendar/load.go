@@ -0,0 +1,168 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package calendar
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+/*
+================
+Calendar Loading
+================
+*/
+
+/*
+dateLayout is the layout of the dates in the calendar files.
+*/
+const dateLayout = "2006-01-02"
+
+/*
+LoadCSV reads a calendar with the given name and a Saturday and Sunday
+weekend from r, a CSV file with one holiday per record. The first field of
+each record is the date of the holiday in the form yyyy-mm-dd, and any
+further fields (e.g. the name of the holiday) are ignored. A header record
+whose first field is "date", blank lines and lines starting with '#' are
+skipped. It returns the error ErrInvalidCalendar if the file cannot be
+read or a date cannot be parsed.
+
+Usage (example):
+var f, _ = os.Open("holidays.csv")
+var c, e = calendar.LoadCSV("XYZ", f)
+
+where holidays.csv contains, e.g.:
+date,name
+2016-12-25,Christmas Day
+2016-12-26,Boxing Day
+*/
+func LoadCSV(name string, r io.Reader) (*Calendar, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	c, err := NewCalendar(name, Weekend, nil)
+	if err != nil {
+		return nil, err
+	}
+	for n := 0; ; n++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidCalendar(fmt.Sprintf("Cannot read the calendar: %v", err))
+		}
+		field := strings.TrimSpace(rec[0])
+		if n == 0 && strings.EqualFold(field, "date") {
+			continue
+		}
+		t, err := time.Parse(dateLayout, field)
+		if err != nil {
+			return nil, ErrInvalidCalendar(fmt.Sprintf("Cannot parse the date %q.", field))
+		}
+		c.AddHoliday(t)
+	}
+	return c, nil
+}
+
+/*
+calendarFile is an unexported type that holds the contents of a JSON
+calendar file.
+*/
+type calendarFile struct {
+	Name     string   `json:"name"`
+	Weekend  []string `json:"weekend"`
+	Holidays []string `json:"holidays"`
+}
+
+/*
+LoadJSON reads a calendar from r, a JSON file of an object with the name
+of the calendar, the names of its weekend days (a Saturday and Sunday
+weekend is assumed if they are omitted), and the dates of its holidays in
+the form yyyy-mm-dd. It returns the error ErrInvalidCalendar if the file
+cannot be read or decoded, a weekday or date cannot be parsed, or all
+seven days of the week are weekend days.
+
+Usage (example):
+var f, _ = os.Open("holidays.json")
+var c, e = calendar.LoadJSON(f)
+
+where holidays.json contains, e.g.:
+{
+	"name": "XYZ",
+	"weekend": ["Saturday", "Sunday"],
+	"holidays": ["2016-12-25", "2016-12-26"]
+}
+*/
+func LoadJSON(r io.Reader) (*Calendar, error) {
+	var f calendarFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, ErrInvalidCalendar(fmt.Sprintf("Cannot read the calendar: %v", err))
+	}
+	weekend := Weekend
+	if f.Weekend != nil {
+		weekend = make([]time.Weekday, len(f.Weekend))
+		for i, s := range f.Weekend {
+			d, ok := weekdays[strings.ToLower(s)]
+			if !ok {
+				return nil, ErrInvalidCalendar(fmt.Sprintf("Cannot parse the weekday %q.", s))
+			}
+			weekend[i] = d
+		}
+	}
+	c, err := NewCalendar(f.Name, weekend, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range f.Holidays {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return nil, ErrInvalidCalendar(fmt.Sprintf("Cannot parse the date %q.", s))
+		}
+		c.AddHoliday(t)
+	}
+	return c, nil
+}
+
+/*
+weekdays maps the lower-case names of the days of the week to their
+values.
+*/
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
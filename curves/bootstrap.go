@@ -0,0 +1,363 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package curves
+
+import (
+	"fmt"
+	. "github.com/kervinlow/quantstruct/math"
+	. "math"
+	"sort"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInconsistentInstruments is returned when a set of instruments
+cannot be fitted by a single curve, e.g. when two instruments share the same
+pillar, or when no discount factor reprices an instrument to its quote.
+*/
+type ErrInconsistentInstruments string
+
+func (e ErrInconsistentInstruments) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+The error ErrNonMonotone is returned when a bootstrapped curve has a
+discount factor that is not below the discount factor at the previous
+pillar, i.e. when the quotes imply a negative forward rate.
+*/
+type ErrNonMonotone string
+
+func (e ErrNonMonotone) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+====================
+Curve Bootstrapping
+====================
+*/
+
+const (
+	bootstrapTolerance     = 1.0e-14 // tolerance on the zero rate at each pillar
+	bootstrapMaxIterations = 100     // maximum number of root finder iterations
+	repriceTolerance       = 1.0e-10 // tolerance on the repriced quotes
+	minZeroRate            = -1.0    // lower end of the zero rate search
+	maxZeroRate            = 2.0     // upper end of the zero rate search
+)
+
+/*
+Instrument is the interface that wraps the methods of a market instrument
+that a curve is bootstrapped from. Times are expressed as year fractions
+from the valuation date.
+
+Pillar returns the time of the last cash flow of the instrument, which
+becomes a pillar of the bootstrapped curve.
+
+Residual returns the difference between the rate of the instrument implied
+by the curve c and its quoted rate.
+*/
+type Instrument interface {
+	Pillar() float64
+	Residual(c DiscountCurve) float64
+}
+
+/*
+Deposit represents a deposit from the valuation date to the time Maturity,
+quoted at the simply compounded rate Rate.
+
+Usage (example):
+var d = curves.Deposit{0.25, 0.045}
+*/
+type Deposit struct {
+	Maturity float64
+	Rate     float64
+}
+
+/*
+Pillar returns the maturity of the deposit.
+*/
+func (d Deposit) Pillar() float64 {
+	return d.Maturity
+}
+
+/*
+Residual returns the difference between the deposit rate implied by the
+curve c and the quoted rate.
+*/
+func (d Deposit) Residual(c DiscountCurve) float64 {
+	return simpleRate(c, 0.0, d.Maturity) - d.Rate
+}
+
+/*
+FRA represents a forward rate agreement from the time Start to the time
+End, quoted at the simply compounded rate Rate.
+
+Usage (example):
+var f = curves.FRA{0.25, 0.5, 0.046}
+*/
+type FRA struct {
+	Start float64
+	End   float64
+	Rate  float64
+}
+
+/*
+Pillar returns the end of the period of the FRA.
+*/
+func (f FRA) Pillar() float64 {
+	return f.End
+}
+
+/*
+Residual returns the difference between the forward rate implied by the
+curve c and the quoted rate.
+*/
+func (f FRA) Residual(c DiscountCurve) float64 {
+	return simpleRate(c, f.Start, f.End) - f.Rate
+}
+
+/*
+Future represents an interest rate future on the rate from the time Start
+to the time End, quoted at the price Price (i.e. 100 less the futures rate
+in percent). The futures rate is converted to a forward rate with the
+convexity adjustment 1/2 * Volatility^2 * Start * End of the Ho and Lee
+model, where Volatility is the normal volatility of the short rate.
+
+Usage (example):
+var f = curves.Future{0.5, 0.75, 95.25, 0.01}
+*/
+type Future struct {
+	Start      float64
+	End        float64
+	Price      float64
+	Volatility float64
+}
+
+/*
+Pillar returns the end of the period of the future.
+*/
+func (f Future) Pillar() float64 {
+	return f.End
+}
+
+/*
+Residual returns the difference between the forward rate implied by the
+curve c and the convexity-adjusted futures rate.
+*/
+func (f Future) Residual(c DiscountCurve) float64 {
+	return simpleRate(c, f.Start, f.End) - f.ForwardRate()
+}
+
+/*
+ForwardRate returns the simply compounded forward rate implied by the
+futures price, after the convexity adjustment.
+*/
+func (f Future) ForwardRate() float64 {
+	return (100.0-f.Price)/100.0 - 0.5*f.Volatility*f.Volatility*f.Start*f.End
+}
+
+/*
+Swap represents a spot-starting interest rate swap to the time Maturity,
+quoted at the par fixed rate Rate, whose fixed leg pays Frequency times a
+year (with a short first period if the maturity is not a whole number of
+periods). The floating leg is assumed to be valued at par on the same
+curve.
+
+Usage (example):
+var s = curves.Swap{5.0, 0.05, 2}
+*/
+type Swap struct {
+	Maturity  float64
+	Rate      float64
+	Frequency int
+}
+
+/*
+Pillar returns the maturity of the swap.
+*/
+func (s Swap) Pillar() float64 {
+	return s.Maturity
+}
+
+/*
+Residual returns the difference between the par rate implied by the curve
+c and the quoted rate.
+*/
+func (s Swap) Residual(c DiscountCurve) float64 {
+	// Accumulate the annuity of the fixed leg backwards from the maturity.
+	annuity := 0.0
+	for end := s.Maturity; end > 0.0; {
+		start := Max(end-1.0/float64(s.Frequency), 0.0)
+		if start < 1.0e-9 {
+			start = 0.0
+		}
+		annuity += (end - start) * c.DiscountFactor(end)
+		end = start
+	}
+	return (1.0-c.DiscountFactor(s.Maturity))/annuity - s.Rate
+}
+
+/*
+byPillar is an unexported type that sorts instruments by their pillars.
+*/
+type byPillar []Instrument
+
+func (p byPillar) Len() int           { return len(p) }
+func (p byPillar) Less(i, j int) bool { return p[i].Pillar() < p[j].Pillar() }
+func (p byPillar) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+/*
+simpleRate is an unexported function that returns the simply compounded
+rate from time t1 to time t2 implied by the curve c.
+*/
+func simpleRate(c DiscountCurve, t1 float64, t2 float64) float64 {
+	return (c.DiscountFactor(t1)/c.DiscountFactor(t2) - 1.0) / (t2 - t1)
+}
+
+/*
+----------------------------------------------------------------------
+Bootstrap -- Curve bootstrapping
+
+Description:
+A function that builds a curve that interpolates log-linearly in the
+discount factors from a set of deposits, FRAs, interest rate futures and
+par swaps, by solving for the discount factor at the pillar of each
+instrument in turn, in order of the pillars, so that the instrument is
+repriced to its quote. It returns the error ErrInconsistentInstruments
+if an instrument is nil, if two instruments share a pillar, if an
+instrument has an invalid period, if no discount factor reprices an
+instrument, or if the curve fails to reprice all the instruments to
+within tolerance; the error ErrNonMonotone if the discount factors are
+not strictly decreasing; otherwise, it returns nil.
+
+Usage:
+var c, err = curves.Bootstrap([]curves.Instrument{
+	curves.Deposit{0.25, 0.045},
+	curves.FRA{0.25, 0.5, 0.046},
+	curves.Swap{2.0, 0.048, 2},
+})
+
+Arguments:
+instruments the instruments to bootstrap the curve from (any
+            type that implements the curves.Instrument interface)
+----------------------------------------------------------------------
+*/
+func Bootstrap(instruments []Instrument) (*LogLinearDiscountCurve, error) {
+	if len(instruments) == 0 {
+		return nil, ErrInconsistentInstruments("At least 1 instrument is needed.")
+	}
+	for _, inst := range instruments {
+		if inst == nil {
+			return nil, ErrInconsistentInstruments("The instruments must not be nil.")
+		}
+	}
+	sorted := append(byPillar(nil), instruments...)
+	sort.Stable(sorted)
+	times := make([]float64, 0, len(sorted))
+	dfs := make([]float64, 0, len(sorted))
+	for i, inst := range sorted {
+		t := inst.Pillar()
+		if t <= 0.0 || IsNaN(t) {
+			return nil, ErrInconsistentInstruments("The pillars must be positive.")
+		}
+		if i > 0 && t == times[i-1] {
+			return nil, ErrInconsistentInstruments(fmt.Sprintf("Two instruments share the pillar %v.", t))
+		}
+		if err := checkPeriod(inst, t); err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+		dfs = append(dfs, 1.0)
+		// Solve for the zero rate at the new pillar.
+		var errCurve error
+		f := func(z float64) float64 {
+			dfs[i] = Exp(-z * t)
+			c, err := NewLogLinearDiscountCurve(times, dfs)
+			if err != nil {
+				errCurve = err
+				return NaN()
+			}
+			return inst.Residual(c)
+		}
+		z, err := Brent(f, minZeroRate, maxZeroRate, bootstrapTolerance, bootstrapMaxIterations)
+		if errCurve != nil {
+			return nil, errCurve
+		}
+		if err != nil {
+			return nil, ErrInconsistentInstruments(fmt.Sprintf("No discount factor reprices the instrument with pillar %v.", t))
+		}
+		dfs[i] = Exp(-z * t)
+		prev := 1.0
+		if i > 0 {
+			prev = dfs[i-1]
+		}
+		if dfs[i] >= prev {
+			return nil, ErrNonMonotone(fmt.Sprintf("The discount factor at pillar %v is not below the previous one.", t))
+		}
+	}
+	c, err := NewLogLinearDiscountCurve(times, dfs)
+	if err != nil {
+		return nil, err
+	}
+	// Check that the final curve reprices every instrument.
+	for _, inst := range sorted {
+		if res := inst.Residual(c); IsNaN(res) || Abs(res) > repriceTolerance {
+			return nil, ErrInconsistentInstruments(fmt.Sprintf("The instrument with pillar %v is repriced with a residual of %v.", inst.Pillar(), res))
+		}
+	}
+	return c, nil
+}
+
+/*
+checkPeriod is an unexported function that returns the error
+ErrInconsistentInstruments if the instrument inst, with pillar t, has a
+period that is empty or that starts before the valuation date, or a
+fixed leg frequency that is not positive; otherwise, it returns nil.
+*/
+func checkPeriod(inst Instrument, t float64) error {
+	var start float64
+	switch v := inst.(type) {
+	case FRA:
+		start = v.Start
+	case Future:
+		start = v.Start
+	case Swap:
+		if v.Frequency <= 0 {
+			return ErrInconsistentInstruments("The frequency of a swap must be positive.")
+		}
+	}
+	if start < 0.0 || start >= t {
+		return ErrInconsistentInstruments(fmt.Sprintf("The instrument with pillar %v has an invalid period.", t))
+	}
+	return nil
+}
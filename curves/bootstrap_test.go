@@ -0,0 +1,92 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package curves
+
+import (
+	. "math"
+	"testing"
+)
+
+func TestBootstrapReprices(t *testing.T) {
+	instruments := []Instrument{
+		Swap{Maturity: 5.0, Rate: 0.05, Frequency: 2},
+		Deposit{Maturity: 0.25, Rate: 0.045},
+		FRA{Start: 0.25, End: 0.5, Rate: 0.046},
+		Future{Start: 0.5, End: 0.75, Price: 95.25, Volatility: 0.01},
+		Swap{Maturity: 2.0, Rate: 0.048, Frequency: 2},
+		Swap{Maturity: 3.5, Rate: 0.049, Frequency: 1},
+	}
+	c, err := Bootstrap(instruments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, inst := range instruments {
+		if res := inst.Residual(c); Abs(res) > 1.0e-10 {
+			t.Errorf("%+v is repriced with a residual of %v", inst, res)
+		}
+	}
+	// The deposit fixes the first discount factor.
+	if df := c.DiscountFactor(0.25); Abs(df-1.0/(1.0+0.25*0.045)) > 1.0e-12 {
+		t.Errorf("DiscountFactor(0.25) = %v, want %v", df, 1.0/(1.0+0.25*0.045))
+	}
+	// The convexity adjustment lowers the forward rate below the futures rate.
+	f := Future{Start: 0.5, End: 0.75, Price: 95.25, Volatility: 0.01}
+	if want := 0.0475 - 0.5*0.01*0.01*0.5*0.75; Abs(f.ForwardRate()-want) > 1.0e-15 {
+		t.Errorf("Future.ForwardRate() = %v, want %v", f.ForwardRate(), want)
+	}
+}
+
+func TestBootstrapInconsistentInstruments(t *testing.T) {
+	cases := []struct {
+		name        string
+		instruments []Instrument
+	}{
+		{"no instruments", nil},
+		{"a nil instrument", []Instrument{Deposit{Maturity: 0.25, Rate: 0.045}, nil}},
+		{"a shared pillar", []Instrument{Deposit{Maturity: 0.5, Rate: 0.045}, FRA{Start: 0.25, End: 0.5, Rate: 0.046}}},
+		{"an empty FRA period", []Instrument{FRA{Start: 0.5, End: 0.5, Rate: 0.046}}},
+		{"a swap without a frequency", []Instrument{Swap{Maturity: 2.0, Rate: 0.048}}},
+		{"an unreachable quote", []Instrument{Deposit{Maturity: 0.25, Rate: 100.0}}},
+	}
+	for _, c := range cases {
+		_, err := Bootstrap(c.instruments)
+		if _, ok := err.(ErrInconsistentInstruments); !ok {
+			t.Errorf("Bootstrap with %s returned %v, want ErrInconsistentInstruments", c.name, err)
+		}
+	}
+}
+
+func TestBootstrapNonMonotone(t *testing.T) {
+	// A negative FRA rate implies a discount factor above the previous one.
+	_, err := Bootstrap([]Instrument{
+		Deposit{Maturity: 0.5, Rate: 0.03},
+		FRA{Start: 0.5, End: 1.0, Rate: -0.01},
+	})
+	if _, ok := err.(ErrNonMonotone); !ok {
+		t.Errorf("Bootstrap with a negative forward rate returned %v, want ErrNonMonotone", err)
+	}
+}
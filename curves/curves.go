@@ -0,0 +1,172 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+/*
+Package curves provides the representations of interest rate curves.
+
+This is a multi-file package and is made up of the following source files:
+  bootstrap.go      provides the bootstrapping of a curve from the quotes of
+                    market instruments;
+  curves.go         provides the common definitions that are used by the
+                    other source files in the package, and the flat curve;
+  interpolated.go   provides the curves that interpolate linearly in the
+                    zero rates or in the logarithms of the discount factors;
+  monotoneconvex.go provides the curve that interpolates with the monotone
+                    convex method of Hagan and West.
+*/
+package curves
+
+import (
+	"fmt"
+	. "math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidCurve is returned when the pillars of a curve are
+invalid.
+*/
+type ErrInvalidCurve string
+
+func (e ErrInvalidCurve) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+==================
+Common Definitions
+==================
+*/
+
+/*
+DiscountCurve is the interface that wraps the methods of an interest rate
+curve. Times are expressed as year fractions from the valuation date, and
+rates are continuously compounded.
+
+DiscountFactor returns the discount factor from time t to the valuation
+date.
+
+ZeroRate returns the zero rate from the valuation date to time t.
+
+ForwardRate returns the forward rate from time t1 to time t2.
+*/
+type DiscountCurve interface {
+	DiscountFactor(t float64) float64
+	ZeroRate(t float64) float64
+	ForwardRate(t1 float64, t2 float64) float64
+}
+
+/*
+instant is the time step used to compute the zero rate at time zero and
+instantaneous forward rates from the discount factors.
+*/
+const instant = 1.0e-6
+
+/*
+zeroRate is an unexported function that returns the zero rate to time t
+implied by the discount factors of a curve.
+*/
+func zeroRate(df func(float64) float64, t float64) float64 {
+	if t <= 0.0 {
+		return -Log(df(instant)) / instant
+	}
+	return -Log(df(t)) / t
+}
+
+/*
+forwardRate is an unexported function that returns the forward rate from
+time t1 to time t2 implied by the discount factors of a curve.
+*/
+func forwardRate(df func(float64) float64, t1 float64, t2 float64) float64 {
+	if t2 == t1 {
+		t2 = t1 + instant
+	}
+	return Log(df(t1)/df(t2)) / (t2 - t1)
+}
+
+/*
+checkPillars is an unexported function that returns the error
+ErrInvalidCurve if the pillar times and values are of different lengths,
+are empty, or if the times are not positive and strictly increasing;
+otherwise, it returns nil.
+*/
+func checkPillars(times []float64, values []float64) error {
+	if len(times) != len(values) {
+		return ErrInvalidCurve("The two slices given are of different length.")
+	}
+	if len(times) == 0 {
+		return ErrInvalidCurve("The curve needs at least 1 pillar.")
+	}
+	for i, t := range times {
+		if t <= 0.0 || (i > 0 && t <= times[i-1]) || IsNaN(values[i]) {
+			return ErrInvalidCurve("The pillar times must be positive and strictly increasing.")
+		}
+	}
+	return nil
+}
+
+/*
+----------
+Flat Curve
+----------
+*/
+
+/*
+FlatCurve represents a curve with the same zero rate for all times.
+
+Usage (example):
+var c = curves.FlatCurve{0.05}
+*/
+type FlatCurve struct {
+	Rate float64
+}
+
+/*
+DiscountFactor returns the discount factor from time t to the valuation
+date.
+*/
+func (c FlatCurve) DiscountFactor(t float64) float64 {
+	return Exp(-c.Rate * t)
+}
+
+/*
+ZeroRate returns the zero rate from the valuation date to time t.
+*/
+func (c FlatCurve) ZeroRate(t float64) float64 {
+	return c.Rate
+}
+
+/*
+ForwardRate returns the forward rate from time t1 to time t2.
+*/
+func (c FlatCurve) ForwardRate(t1 float64, t2 float64) float64 {
+	return c.Rate
+}
@@ -0,0 +1,125 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package curves
+
+import (
+	. "math"
+	"testing"
+)
+
+var (
+	pillarTimes = []float64{0.25, 0.5, 1.0, 2.0, 5.0}
+	pillarRates = []float64{0.02, 0.025, 0.03, 0.028, 0.035}
+)
+
+func TestInterpolatorsReproducePillars(t *testing.T) {
+	dfs := make([]float64, len(pillarTimes))
+	for i, x := range pillarTimes {
+		dfs[i] = Exp(-pillarRates[i] * x)
+	}
+	linear, err := NewLinearZeroCurve(pillarTimes, pillarRates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logLinear, err := NewLogLinearDiscountCurve(pillarTimes, dfs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	convex, err := NewMonotoneConvexCurve(pillarTimes, pillarRates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name  string
+		curve DiscountCurve
+	}{
+		{"LinearZeroCurve", linear},
+		{"LogLinearDiscountCurve", logLinear},
+		{"MonotoneConvexCurve", convex},
+	} {
+		for i, x := range pillarTimes {
+			if r := c.curve.ZeroRate(x); Abs(r-pillarRates[i]) > 1.0e-12 {
+				t.Errorf("%s.ZeroRate(%v) = %v, want %v", c.name, x, r, pillarRates[i])
+			}
+			if df := c.curve.DiscountFactor(x); Abs(df-dfs[i]) > 1.0e-12 {
+				t.Errorf("%s.DiscountFactor(%v) = %v, want %v", c.name, x, df, dfs[i])
+			}
+		}
+		// The forward rates between the pillars are implied by the zero rates.
+		for i := 1; i < len(pillarTimes); i++ {
+			t1, t2 := pillarTimes[i-1], pillarTimes[i]
+			want := (pillarRates[i]*t2 - pillarRates[i-1]*t1) / (t2 - t1)
+			if f := c.curve.ForwardRate(t1, t2); Abs(f-want) > 1.0e-12 {
+				t.Errorf("%s.ForwardRate(%v, %v) = %v, want %v", c.name, t1, t2, f, want)
+			}
+		}
+	}
+}
+
+func TestMonotoneConvexPositiveForwards(t *testing.T) {
+	// The discrete forward rates are positive, but jump from 1% to 8% and
+	// back, so that the unbounded instantaneous forward rates would become
+	// negative at the short end.
+	rates := []float64{0.01, 0.045, 0.03, 0.035, 0.03}
+	c, err := NewMonotoneConvexCurve([]float64{0.5, 1.0, 2.0, 3.0, 5.0}, rates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0.0; x <= 6.0; x += 0.001 {
+		if f := c.ForwardRate(x, x+1.0e-6); f < 0.0 {
+			t.Fatalf("MonotoneConvexCurve.ForwardRate(%v) = %v, want a non-negative rate", x, f)
+		}
+	}
+	for i, x := range []float64{0.5, 1.0, 2.0, 3.0, 5.0} {
+		if r := c.ZeroRate(x); Abs(r-rates[i]) > 1.0e-12 {
+			t.Errorf("MonotoneConvexCurve.ZeroRate(%v) = %v, want %v", x, r, rates[i])
+		}
+	}
+}
+
+func TestInvalidPillars(t *testing.T) {
+	cases := []struct {
+		name         string
+		times, rates []float64
+	}{
+		{"different lengths", []float64{0.5, 1.0}, []float64{0.02}},
+		{"no pillars", nil, nil},
+		{"non-increasing times", []float64{1.0, 0.5}, []float64{0.02, 0.03}},
+		{"zero time", []float64{0.0, 0.5}, []float64{0.02, 0.03}},
+	}
+	for _, c := range cases {
+		if _, err := NewLinearZeroCurve(c.times, c.rates); err == nil {
+			t.Errorf("NewLinearZeroCurve with %s returned no error", c.name)
+		}
+		if _, err := NewMonotoneConvexCurve(c.times, c.rates); err == nil {
+			t.Errorf("NewMonotoneConvexCurve with %s returned no error", c.name)
+		}
+	}
+	if _, err := NewLogLinearDiscountCurve([]float64{0.5}, []float64{-0.9}); err == nil {
+		t.Error("NewLogLinearDiscountCurve with a negative discount factor returned no error")
+	}
+}
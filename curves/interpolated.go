@@ -0,0 +1,167 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package curves
+
+import (
+	. "math"
+	"sort"
+)
+
+/*
+-----------------------------------------
+Curve Linearly Interpolated in Zero Rates
+-----------------------------------------
+*/
+
+/*
+LinearZeroCurve represents a curve whose zero rates are interpolated
+linearly between its pillars, and held flat before the first pillar and
+after the last pillar. It is created by NewLinearZeroCurve.
+*/
+type LinearZeroCurve struct {
+	times []float64
+	rates []float64
+}
+
+/*
+NewLinearZeroCurve creates a curve that interpolates linearly in the zero
+rates at the given pillar times. It returns the error ErrInvalidCurve if
+the pillars are invalid.
+
+Usage (example):
+var c, e = curves.NewLinearZeroCurve([]float64{0.5, 1.0, 2.0}, []float64{0.04, 0.045, 0.05})
+*/
+func NewLinearZeroCurve(times []float64, rates []float64) (*LinearZeroCurve, error) {
+	if err := checkPillars(times, rates); err != nil {
+		return nil, err
+	}
+	return &LinearZeroCurve{append([]float64(nil), times...), append([]float64(nil), rates...)}, nil
+}
+
+/*
+DiscountFactor returns the discount factor from time t to the valuation
+date.
+*/
+func (c *LinearZeroCurve) DiscountFactor(t float64) float64 {
+	return Exp(-c.ZeroRate(t) * t)
+}
+
+/*
+ZeroRate returns the zero rate from the valuation date to time t.
+*/
+func (c *LinearZeroCurve) ZeroRate(t float64) float64 {
+	n := len(c.times)
+	i := sort.SearchFloat64s(c.times, t)
+	switch {
+	case i == 0:
+		return c.rates[0]
+	case i == n:
+		return c.rates[n-1]
+	}
+	w := (t - c.times[i-1]) / (c.times[i] - c.times[i-1])
+	return (1.0-w)*c.rates[i-1] + w*c.rates[i]
+}
+
+/*
+ForwardRate returns the forward rate from time t1 to time t2.
+*/
+func (c *LinearZeroCurve) ForwardRate(t1 float64, t2 float64) float64 {
+	return forwardRate(c.DiscountFactor, t1, t2)
+}
+
+/*
+---------------------------------------------------
+Curve Log-Linearly Interpolated in Discount Factors
+---------------------------------------------------
+*/
+
+/*
+LogLinearDiscountCurve represents a curve whose discount factors are
+interpolated linearly in their logarithms between its pillars (i.e. with
+piecewise flat forward rates), starting from a discount factor of 1 at
+time zero; the forward rate of the last segment is extended after the last
+pillar. It is created by NewLogLinearDiscountCurve.
+*/
+type LogLinearDiscountCurve struct {
+	times []float64
+	logs  []float64
+}
+
+/*
+NewLogLinearDiscountCurve creates a curve that interpolates linearly in
+the logarithms of the discount factors at the given pillar times. It
+returns the error ErrInvalidCurve if the pillars are invalid or any
+discount factor is not positive.
+
+Usage (example):
+var c, e = curves.NewLogLinearDiscountCurve([]float64{0.5, 1.0}, []float64{0.98, 0.955})
+*/
+func NewLogLinearDiscountCurve(times []float64, dfs []float64) (*LogLinearDiscountCurve, error) {
+	if err := checkPillars(times, dfs); err != nil {
+		return nil, err
+	}
+	c := &LogLinearDiscountCurve{[]float64{0.0}, []float64{0.0}}
+	for i, df := range dfs {
+		if df <= 0.0 {
+			return nil, ErrInvalidCurve("The discount factors must be positive.")
+		}
+		c.times = append(c.times, times[i])
+		c.logs = append(c.logs, Log(df))
+	}
+	return c, nil
+}
+
+/*
+DiscountFactor returns the discount factor from time t to the valuation
+date.
+*/
+func (c *LogLinearDiscountCurve) DiscountFactor(t float64) float64 {
+	n := len(c.times)
+	i := sort.SearchFloat64s(c.times, t)
+	switch {
+	case i == 0:
+		i = 1
+	case i == n:
+		i = n - 1
+	}
+	w := (t - c.times[i-1]) / (c.times[i] - c.times[i-1])
+	return Exp((1.0-w)*c.logs[i-1] + w*c.logs[i])
+}
+
+/*
+ZeroRate returns the zero rate from the valuation date to time t.
+*/
+func (c *LogLinearDiscountCurve) ZeroRate(t float64) float64 {
+	return zeroRate(c.DiscountFactor, t)
+}
+
+/*
+ForwardRate returns the forward rate from time t1 to time t2.
+*/
+func (c *LogLinearDiscountCurve) ForwardRate(t1 float64, t2 float64) float64 {
+	return forwardRate(c.DiscountFactor, t1, t2)
+}
@@ -0,0 +1,188 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package curves
+
+import (
+	. "math"
+	"sort"
+)
+
+/*
+----------------------------------
+Monotone Convex Interpolated Curve
+----------------------------------
+*/
+
+/*
+MonotoneConvexCurve represents a curve that interpolates its zero rates
+with the monotone convex method of Hagan and West (2006), which yields
+continuous instantaneous forward rates that preserve the monotonicity and
+convexity of the discrete forward rates between the pillars, and do not
+become negative if the discrete forward rates are positive; the last
+instantaneous forward rate is extended after the last pillar. It is
+created by NewMonotoneConvexCurve.
+*/
+type MonotoneConvexCurve struct {
+	times    []float64 // pillar times, starting at time zero
+	rates    []float64 // zero rates at the pillar times
+	discrete []float64 // discrete forward rate of the segment ending at times[i]
+	instant  []float64 // instantaneous forward rate at times[i]
+}
+
+/*
+NewMonotoneConvexCurve creates a curve that interpolates with the monotone
+convex method through the zero rates at the given pillar times. It returns
+the error ErrInvalidCurve if the pillars are invalid.
+
+Usage (example):
+var c, e = curves.NewMonotoneConvexCurve([]float64{0.5, 1.0, 2.0}, []float64{0.04, 0.045, 0.05})
+*/
+func NewMonotoneConvexCurve(times []float64, rates []float64) (*MonotoneConvexCurve, error) {
+	if err := checkPillars(times, rates); err != nil {
+		return nil, err
+	}
+	n := len(times)
+	c := &MonotoneConvexCurve{
+		times:    append([]float64{0.0}, times...),
+		rates:    append([]float64{rates[0]}, rates...),
+		discrete: make([]float64, n+1),
+		instant:  make([]float64, n+1),
+	}
+	for i := 1; i <= n; i++ {
+		c.discrete[i] = (c.rates[i]*c.times[i] - c.rates[i-1]*c.times[i-1]) / (c.times[i] - c.times[i-1])
+	}
+	if n == 1 {
+		c.instant[0], c.instant[1] = c.discrete[1], c.discrete[1]
+		return c, nil
+	}
+	// The instantaneous forward rates at the interior pillars are weighted
+	// averages of the adjacent discrete forward rates, and those at the ends
+	// are extrapolated so that the ends are not overly curved.
+	for i := 1; i < n; i++ {
+		t0, t1, t2 := c.times[i-1], c.times[i], c.times[i+1]
+		c.instant[i] = (t1-t0)/(t2-t0)*c.discrete[i+1] + (t2-t1)/(t2-t0)*c.discrete[i]
+	}
+	c.instant[0] = c.discrete[1] - 0.5*(c.instant[1]-c.discrete[1])
+	c.instant[n] = c.discrete[n] - 0.5*(c.instant[n-1]-c.discrete[n])
+	// If the discrete forward rates are all positive, bound the instantaneous
+	// forward rates so that they do not become negative between the pillars.
+	positive := true
+	for i := 1; i <= n; i++ {
+		positive = positive && c.discrete[i] > 0.0
+	}
+	if positive {
+		c.instant[0] = Min(Max(c.instant[0], 0.0), 2.0*c.discrete[1])
+		for i := 1; i < n; i++ {
+			c.instant[i] = Min(Max(c.instant[i], 0.0), 2.0*Min(c.discrete[i], c.discrete[i+1]))
+		}
+		c.instant[n] = Min(Max(c.instant[n], 0.0), 2.0*c.discrete[n])
+	}
+	return c, nil
+}
+
+/*
+DiscountFactor returns the discount factor from time t to the valuation
+date.
+*/
+func (c *MonotoneConvexCurve) DiscountFactor(t float64) float64 {
+	return Exp(-c.integral(t))
+}
+
+/*
+ZeroRate returns the zero rate from the valuation date to time t.
+*/
+func (c *MonotoneConvexCurve) ZeroRate(t float64) float64 {
+	if t <= 0.0 {
+		return c.instant[0]
+	}
+	return c.integral(t) / t
+}
+
+/*
+ForwardRate returns the forward rate from time t1 to time t2.
+*/
+func (c *MonotoneConvexCurve) ForwardRate(t1 float64, t2 float64) float64 {
+	return forwardRate(c.DiscountFactor, t1, t2)
+}
+
+/*
+integral is an unexported method that returns the integral of the
+instantaneous forward rate from time zero to time t, i.e. the zero rate to
+time t multiplied by t.
+*/
+func (c *MonotoneConvexCurve) integral(t float64) float64 {
+	n := len(c.times) - 1
+	if t <= 0.0 {
+		return 0.0
+	}
+	if t >= c.times[n] {
+		return c.rates[n]*c.times[n] + c.instant[n]*(t-c.times[n])
+	}
+	i := sort.SearchFloat64s(c.times, t)
+	h := c.times[i] - c.times[i-1]
+	x := (t - c.times[i-1]) / h
+	g0, g1 := c.instant[i-1]-c.discrete[i], c.instant[i]-c.discrete[i]
+	return c.rates[i-1]*c.times[i-1] + c.discrete[i]*(t-c.times[i-1]) + h*monotoneConvexG(g0, g1, x)
+}
+
+/*
+monotoneConvexG is an unexported function that returns the integral from 0
+to x of the monotone convex adjustment g to the discrete forward rate of a
+segment, given the adjustments g0 and g1 at the ends of the segment, where
+x is the position within the segment scaled to [0, 1].
+*/
+func monotoneConvexG(g0 float64, g1 float64, x float64) float64 {
+	switch {
+	case g0 == 0.0 && g1 == 0.0:
+		return 0.0
+	case (g0 < 0.0 && -g0/2.0 <= g1 && g1 <= -2.0*g0) || (g0 > 0.0 && -g0/2.0 >= g1 && g1 >= -2.0*g0):
+		// Sector (i): a single quadratic.
+		return g0*(x-2.0*x*x+x*x*x) + g1*(-x*x+x*x*x)
+	case (g0 < 0.0 && g1 > -2.0*g0) || (g0 > 0.0 && g1 < -2.0*g0):
+		// Sector (ii): flat, then quadratic.
+		eta := (g1 + 2.0*g0) / (g1 - g0)
+		if x <= eta {
+			return g0 * x
+		}
+		return g0*x + (g1-g0)*Pow(x-eta, 3.0)/(3.0*(1.0-eta)*(1.0-eta))
+	case (g0 > 0.0 && 0.0 > g1 && g1 > -g0/2.0) || (g0 < 0.0 && 0.0 < g1 && g1 < -g0/2.0):
+		// Sector (iii): quadratic, then flat.
+		eta := 3.0 * g1 / (g1 - g0)
+		if x < eta {
+			return g1*x + (g0-g1)*(Pow(eta, 3.0)-Pow(eta-x, 3.0))/(3.0*eta*eta)
+		}
+		return g1*x + (g0-g1)*eta/3.0
+	default:
+		// Sector (iv): two quadratics meeting at a common level.
+		eta := g1 / (g1 + g0)
+		a := -g0 * g1 / (g0 + g1)
+		if x <= eta && eta > 0.0 {
+			return a*x + (g0-a)*(Pow(eta, 3.0)-Pow(eta-x, 3.0))/(3.0*eta*eta)
+		}
+		return a*x + (g0-a)*eta/3.0 + (g1-a)*Pow(x-eta, 3.0)/(3.0*(1.0-eta)*(1.0-eta))
+	}
+}
@@ -0,0 +1,210 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package daycount
+
+import (
+	"math"
+	"time"
+)
+
+/*
+-------
+ACT/360
+-------
+*/
+
+/*
+Actual360 represents the ACT/360 convention, which divides the actual
+number of days by 360.
+
+Usage (example):
+var yf = daycount.Actual360{}.YearFraction(start, end)
+*/
+type Actual360 struct{}
+
+/*
+YearFraction returns the year fraction from the start date to the end date.
+*/
+func (c Actual360) YearFraction(start time.Time, end time.Time) float64 {
+	return days(start, end) / 360.0
+}
+
+/*
+--------
+ACT/365F
+--------
+*/
+
+/*
+Actual365Fixed represents the ACT/365 Fixed convention, which divides the
+actual number of days by 365.
+
+Usage (example):
+var yf = daycount.Actual365Fixed{}.YearFraction(start, end)
+*/
+type Actual365Fixed struct{}
+
+/*
+YearFraction returns the year fraction from the start date to the end date.
+*/
+func (c Actual365Fixed) YearFraction(start time.Time, end time.Time) float64 {
+	return days(start, end) / 365.0
+}
+
+/*
+------------
+ACT/ACT ISDA
+------------
+*/
+
+/*
+ActualActualISDA represents the ACT/ACT ISDA convention, which divides the
+actual number of days falling in each calendar year by the number of days
+in that year (365 or 366), and adds up the results.
+
+Usage (example):
+var yf = daycount.ActualActualISDA{}.YearFraction(start, end)
+*/
+type ActualActualISDA struct{}
+
+/*
+YearFraction returns the year fraction from the start date to the end date.
+*/
+func (c ActualActualISDA) YearFraction(start time.Time, end time.Time) float64 {
+	if end.Before(start) {
+		return -c.YearFraction(end, start)
+	}
+	start, end = date(start), date(end)
+	yf := 0.0
+	for y := start.Year(); y <= end.Year(); y++ {
+		// Count the days of the period that fall in the year y.
+		from := time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(y+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if start.After(from) {
+			from = start
+		}
+		if end.Before(to) {
+			to = end
+		}
+		basis := 365.0
+		if isLeap(y) {
+			basis = 366.0
+		}
+		yf += days(from, to) / basis
+	}
+	return yf
+}
+
+/*
+------------
+ACT/ACT ICMA
+------------
+*/
+
+/*
+ActualActualICMA represents the ACT/ACT ICMA convention for a bond paying
+Frequency coupons a year. The period is split into the quasi-coupon
+periods found by stepping back 12/Frequency months at a time from the end
+date, and the actual number of days falling in each quasi-coupon period
+is divided by Frequency times the number of days in that period. The year
+fraction is NaN if Frequency is not a positive divisor of 12.
+
+Usage (example):
+var yf = daycount.ActualActualICMA{2}.YearFraction(start, end)
+*/
+type ActualActualICMA struct {
+	Frequency int
+}
+
+/*
+YearFraction returns the year fraction from the start date to the end date.
+*/
+func (c ActualActualICMA) YearFraction(start time.Time, end time.Time) float64 {
+	if c.Frequency <= 0 || 12%c.Frequency != 0 {
+		return math.NaN()
+	}
+	if end.Before(start) {
+		return -c.YearFraction(end, start)
+	}
+	start, end = date(start), date(end)
+	months := 12 / c.Frequency
+	yf := 0.0
+	for n := 0; ; n++ {
+		// The quasi-coupon period [from, to] is anchored at the end date.
+		to := addMonths(end, -n*months)
+		from := addMonths(end, -(n+1)*months)
+		if !start.Before(from) {
+			yf += days(start, to) / (float64(c.Frequency) * days(from, to))
+			break
+		}
+		yf += 1.0 / float64(c.Frequency)
+	}
+	return yf
+}
+
+/*
+-----------
+ACT/ACT AFB
+-----------
+*/
+
+/*
+ActualActualAFB represents the ACT/ACT AFB convention. Whole years are
+counted back from the end date (a year before 29 February being 28
+February), and the actual number of days in the remaining period is
+divided by 366 if it contains 29 February, or by 365 otherwise.
+
+Usage (example):
+var yf = daycount.ActualActualAFB{}.YearFraction(start, end)
+*/
+type ActualActualAFB struct{}
+
+/*
+YearFraction returns the year fraction from the start date to the end date.
+*/
+func (c ActualActualAFB) YearFraction(start time.Time, end time.Time) float64 {
+	if end.Before(start) {
+		return -c.YearFraction(end, start)
+	}
+	start, end = date(start), date(end)
+	// Step back whole years from the end date, while they fit in the period.
+	years := 0
+	for !addMonths(end, -12*(years+1)).Before(start) {
+		years++
+	}
+	rest := addMonths(end, -12*years)
+	basis := 365.0
+	for y := start.Year(); y <= rest.Year(); y++ {
+		if isLeap(y) {
+			feb29 := time.Date(y, time.February, 29, 0, 0, 0, 0, time.UTC)
+			if feb29.After(start) && !feb29.After(rest) {
+				basis = 366.0
+			}
+		}
+	}
+	return float64(years) + days(start, rest)/basis
+}
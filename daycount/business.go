@@ -0,0 +1,85 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package daycount
+
+import "time"
+
+/*
+BusinessDays is the interface that wraps the IsBusinessDay method of a
+business-day calendar.
+
+IsBusinessDay returns true if the date t is a business day.
+*/
+type BusinessDays interface {
+	IsBusinessDay(t time.Time) bool
+}
+
+/*
+-------
+BUS/252
+-------
+*/
+
+/*
+Business252 represents the BUS/252 convention, which divides the number of
+business days from the start date (inclusive) to the end date (exclusive)
+by 252. The business days are those of Calendar, or the weekdays if
+Calendar is nil.
+
+Usage (example):
+var yf = daycount.Business252{nil}.YearFraction(start, end)
+*/
+type Business252 struct {
+	Calendar BusinessDays
+}
+
+/*
+YearFraction returns the year fraction from the start date to the end date.
+*/
+func (c Business252) YearFraction(start time.Time, end time.Time) float64 {
+	if end.Before(start) {
+		return -c.YearFraction(end, start)
+	}
+	n := 0
+	for d, e := date(start), date(end); d.Before(e); d = d.AddDate(0, 0, 1) {
+		if c.isBusinessDay(d) {
+			n++
+		}
+	}
+	return float64(n) / 252.0
+}
+
+/*
+isBusinessDay is an unexported method that returns true if the date t is a
+business day of the calendar of the convention.
+*/
+func (c Business252) isBusinessDay(t time.Time) bool {
+	if c.Calendar == nil {
+		return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+	}
+	return c.Calendar.IsBusinessDay(t)
+}
@@ -0,0 +1,105 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+/*
+Package daycount provides the day-count conventions that convert a period
+between two dates to a year fraction.
+
+This is a multi-file package and is made up of the following source files:
+  daycount.go  provides the common definitions that are used by the other
+               source files in the package;
+  actual.go    provides the conventions that count the actual number of
+               days (ACT/360, ACT/365F and the ACT/ACT family);
+  thirty360.go provides the conventions that assume 30-day months (30/360
+               US, 30E/360 and 30E/360 ISDA);
+  business.go  provides the convention that counts business days (BUS/252).
+*/
+package daycount
+
+import "time"
+
+/*
+Convention is the interface that wraps the YearFraction method of a
+day-count convention.
+
+YearFraction returns the year fraction from the start date to the end
+date. Only the calendar dates of the arguments are used, i.e. their times
+of day and locations are ignored. The year fraction is negative if the end
+date is before the start date.
+
+A Convention can be passed wherever the equity package expects an
+equity.YearFraction, as the method value c.YearFraction.
+*/
+type Convention interface {
+	YearFraction(start time.Time, end time.Time) float64
+}
+
+/*
+date is an unexported function that returns the calendar date of t as a
+time at midnight UTC.
+*/
+func date(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+/*
+days is an unexported function that returns the actual number of days
+from the start date to the end date.
+*/
+func days(start time.Time, end time.Time) float64 {
+	return float64((date(end).Unix() - date(start).Unix()) / 86400)
+}
+
+/*
+isLeap is an unexported function that returns true if y is a leap year.
+*/
+func isLeap(y int) bool {
+	return y%4 == 0 && (y%100 != 0 || y%400 == 0)
+}
+
+/*
+isLastDayOfMonth is an unexported function that returns true if t is the
+last day of its month.
+*/
+func isLastDayOfMonth(t time.Time) bool {
+	return date(t).AddDate(0, 0, 1).Day() == 1
+}
+
+/*
+addMonths is an unexported function that returns the date n months after
+t, clamped to the last day of the month if the day does not exist in that
+month (e.g. 31 January plus one month is 28 or 29 February).
+*/
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	if d > last {
+		d = last
+	}
+	return time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, time.UTC)
+}
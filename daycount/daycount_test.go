@@ -0,0 +1,127 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package daycount
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func ymd(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestActualActual(t *testing.T) {
+	// The examples of the ISDA paper on the ACT/ACT conventions, for periods
+	// that cross a year end.
+	cases := []struct {
+		start, end      time.Time
+		frequency       int
+		isda, icma, afb float64
+	}{
+		// Short period over 29 February.
+		{ymd(2003, time.November, 1), ymd(2004, time.May, 1), 2, 61.0/365.0 + 121.0/366.0, 0.5, 182.0 / 366.0},
+		// Regular annual period over 29 February.
+		{ymd(1999, time.July, 1), ymd(2000, time.July, 1), 1, 184.0/365.0 + 182.0/366.0, 1.0, 1.0},
+		// Short period that ends in a leap year before 29 February.
+		{ymd(1999, time.July, 15), ymd(2000, time.January, 15), 2, 170.0/365.0 + 14.0/366.0, 0.5, 184.0 / 365.0},
+		// Long period over the year end of two common years.
+		{ymd(2002, time.August, 15), ymd(2003, time.July, 15), 1, 334.0 / 365.0, 334.0 / 365.0, 334.0 / 365.0},
+	}
+	for _, c := range cases {
+		if yf := (ActualActualISDA{}).YearFraction(c.start, c.end); math.Abs(yf-c.isda) > 1.0e-12 {
+			t.Errorf("ACT/ACT ISDA from %v to %v = %v, want %v", c.start, c.end, yf, c.isda)
+		}
+		if yf := (ActualActualICMA{c.frequency}).YearFraction(c.start, c.end); math.Abs(yf-c.icma) > 1.0e-12 {
+			t.Errorf("ACT/ACT ICMA from %v to %v = %v, want %v", c.start, c.end, yf, c.icma)
+		}
+		if yf := (ActualActualAFB{}).YearFraction(c.start, c.end); math.Abs(yf-c.afb) > 1.0e-12 {
+			t.Errorf("ACT/ACT AFB from %v to %v = %v, want %v", c.start, c.end, yf, c.afb)
+		}
+		// The year fraction is negative if the dates are swapped.
+		if yf := (ActualActualISDA{}).YearFraction(c.end, c.start); math.Abs(yf+c.isda) > 1.0e-12 {
+			t.Errorf("ACT/ACT ISDA from %v to %v = %v, want %v", c.end, c.start, yf, -c.isda)
+		}
+	}
+	if yf := (ActualActualICMA{5}).YearFraction(ymd(2003, time.November, 1), ymd(2004, time.May, 1)); !math.IsNaN(yf) {
+		t.Errorf("ACT/ACT ICMA with 5 coupons a year = %v, want NaN", yf)
+	}
+}
+
+func TestThirty360ISDA(t *testing.T) {
+	maturity := ymd(2008, time.February, 29)
+	cases := []struct {
+		start, end time.Time
+		want       float64
+	}{
+		{ymd(2007, time.February, 28), ymd(2007, time.August, 30), 180.0 / 360.0},
+		{ymd(2007, time.February, 28), ymd(2008, time.February, 28), 358.0 / 360.0},
+		{ymd(2007, time.August, 31), ymd(2008, time.February, 28), 178.0 / 360.0},
+		// The last day of February is not moved to the 30th at maturity.
+		{ymd(2007, time.August, 31), ymd(2008, time.February, 29), 179.0 / 360.0},
+		{ymd(2008, time.February, 29), ymd(2008, time.August, 31), 180.0 / 360.0},
+		{ymd(2007, time.February, 28), ymd(2007, time.March, 31), 30.0 / 360.0},
+	}
+	for _, c := range cases {
+		if yf := (Thirty360ISDA{maturity}).YearFraction(c.start, c.end); math.Abs(yf-c.want) > 1.0e-12 {
+			t.Errorf("30E/360 ISDA from %v to %v = %v, want %v", c.start, c.end, yf, c.want)
+		}
+	}
+	// Before the maturity, the last day of February is moved to the 30th.
+	if yf := (Thirty360ISDA{ymd(2010, time.June, 30)}).YearFraction(ymd(2007, time.August, 31), ymd(2008, time.February, 29)); math.Abs(yf-0.5) > 1.0e-12 {
+		t.Errorf("30E/360 ISDA from 31 August 2007 to 29 February 2008 = %v, want 0.5", yf)
+	}
+}
+
+/*
+holidays is a business-day calendar of the weekdays that are not in the
+set of holidays.
+*/
+type holidays map[time.Time]bool
+
+func (h holidays) IsBusinessDay(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday && !h[t]
+}
+
+func TestBusiness252(t *testing.T) {
+	start, end := ymd(2017, time.January, 2), ymd(2017, time.January, 16)
+	// The weekdays from Monday 2 January (inclusive) to Monday 16 January
+	// (exclusive).
+	if yf := (Business252{}).YearFraction(start, end); math.Abs(yf-10.0/252.0) > 1.0e-12 {
+		t.Errorf("BUS/252 without a calendar = %v, want %v", yf, 10.0/252.0)
+	}
+	cal := holidays{ymd(2017, time.January, 2): true, ymd(2017, time.January, 16): true, ymd(2017, time.January, 7): true}
+	// Only the holiday on 2 January is lost, as the end date is excluded and
+	// 7 January is a Saturday.
+	if yf := (Business252{cal}).YearFraction(start, end); math.Abs(yf-9.0/252.0) > 1.0e-12 {
+		t.Errorf("BUS/252 with a calendar = %v, want %v", yf, 9.0/252.0)
+	}
+	if yf := (Business252{cal}).YearFraction(end, start); math.Abs(yf+9.0/252.0) > 1.0e-12 {
+		t.Errorf("BUS/252 with the dates swapped = %v, want %v", yf, -9.0/252.0)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package daycount
+
+import "time"
+
+/*
+thirty360 is an unexported function that returns the 30/360 year fraction
+between the dates (y1, m1, d1) and (y2, m2, d2), after the day numbers
+have been adjusted by the convention.
+*/
+func thirty360(y1 int, m1 time.Month, d1 int, y2 int, m2 time.Month, d2 int) float64 {
+	return float64(360*(y2-y1)+30*(int(m2)-int(m1))+(d2-d1)) / 360.0
+}
+
+/*
+---------
+30/360 US
+---------
+*/
+
+/*
+Thirty360US represents the 30/360 US (bond basis) convention, including
+the end-of-February rules: if the start date is the last day of February,
+its day number becomes 30, and if both dates are the last day of February,
+the end date's day number also becomes 30. Then, if the start date's day
+number is 30 or 31, it becomes 30, and if the end date's day number is 31
+while the start date's is 30, it becomes 30.
+
+Usage (example):
+var yf = daycount.Thirty360US{}.YearFraction(start, end)
+*/
+type Thirty360US struct{}
+
+/*
+YearFraction returns the year fraction from the start date to the end date.
+*/
+func (c Thirty360US) YearFraction(start time.Time, end time.Time) float64 {
+	if end.Before(start) {
+		return -c.YearFraction(end, start)
+	}
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	feb1 := m1 == time.February && isLastDayOfMonth(start)
+	feb2 := m2 == time.February && isLastDayOfMonth(end)
+	if feb1 && feb2 {
+		d2 = 30
+	}
+	if feb1 || d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return thirty360(y1, m1, d1, y2, m2, d2)
+}
+
+/*
+-------
+30E/360
+-------
+*/
+
+/*
+Thirty360European represents the 30E/360 (Eurobond basis) convention,
+where a day number of 31 becomes 30 for both dates.
+
+Usage (example):
+var yf = daycount.Thirty360European{}.YearFraction(start, end)
+*/
+type Thirty360European struct{}
+
+/*
+YearFraction returns the year fraction from the start date to the end date.
+*/
+func (c Thirty360European) YearFraction(start time.Time, end time.Time) float64 {
+	if end.Before(start) {
+		return -c.YearFraction(end, start)
+	}
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 {
+		d2 = 30
+	}
+	return thirty360(y1, m1, d1, y2, m2, d2)
+}
+
+/*
+------------
+30E/360 ISDA
+------------
+*/
+
+/*
+Thirty360ISDA represents the 30E/360 ISDA convention, where the day number
+of a date that is the last day of its month becomes 30, except for an end
+date that is the last day of February and is also the maturity date of the
+instrument, given as Maturity.
+
+Usage (example):
+var yf = daycount.Thirty360ISDA{maturity}.YearFraction(start, end)
+*/
+type Thirty360ISDA struct {
+	Maturity time.Time
+}
+
+/*
+YearFraction returns the year fraction from the start date to the end date.
+*/
+func (c Thirty360ISDA) YearFraction(start time.Time, end time.Time) float64 {
+	if end.Before(start) {
+		return -c.YearFraction(end, start)
+	}
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	if isLastDayOfMonth(start) {
+		d1 = 30
+	}
+	if isLastDayOfMonth(end) && !(m2 == time.February && date(end).Equal(date(c.Maturity))) {
+		d2 = 30
+	}
+	return thirty360(y1, m1, d1, y2, m2, d2)
+}
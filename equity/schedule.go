@@ -0,0 +1,189 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package equity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidDividend is returned when a dated dividend is invalid,
+e.g. when it is paid before it goes ex.
+*/
+type ErrInvalidDividend string
+
+func (e ErrInvalidDividend) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+The error ErrCurrencyMismatch is returned when a dividend is paid in a
+currency other than the currency of the equity instrument.
+*/
+type ErrCurrencyMismatch string
+
+func (e ErrCurrencyMismatch) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+-------------
+Dividend Kind
+-------------
+*/
+
+/*
+DivKind represents the kind of a dated dividend.
+*/
+type DivKind int
+
+/*
+The kinds of dated dividends:
+CashDividend    a regular dividend of a fixed cash amount;
+YieldDividend   a dividend of a fixed fraction of the spot price, i.e. its
+                amount is expressed as a proportion (0.02 for 2%);
+SpecialDividend a one-off dividend of a fixed cash amount.
+*/
+const (
+	CashDividend DivKind = iota
+	YieldDividend
+	SpecialDividend
+)
+
+/*
+--------------
+Dated Dividend
+--------------
+*/
+
+/*
+DatedDiv represents a discrete dividend with its ex-date and pay-date.
+
+Usage (example):
+var d = equity.DatedDiv{
+	ExDate:   time.Date(2016, 9, 1, 0, 0, 0, 0, time.UTC),
+	PayDate:  time.Date(2016, 9, 15, 0, 0, 0, 0, time.UTC),
+	Amount:   4.0,
+	Currency: "USD",
+	Kind:     equity.CashDividend,
+}
+*/
+type DatedDiv struct {
+	ExDate   time.Time
+	PayDate  time.Time
+	Amount   float64 // a cash amount, or a proportion for a YieldDividend
+	Currency string
+	Kind     DivKind
+}
+
+/*
+-----------------
+Dividend Schedule
+-----------------
+*/
+
+/*
+DivSchedule represents a schedule of dated dividends.
+*/
+type DivSchedule []DatedDiv
+
+/*
+YearFraction represents a day-count convention as a function that returns
+the year fraction from the start date to the end date.
+
+Usage (example):
+var yf = equity.YearFraction(func(start, end time.Time) float64 {
+	return end.Sub(start).Hours() / 24.0 / 365.0
+})
+*/
+type YearFraction func(start time.Time, end time.Time) float64
+
+/*
+------------------------------
+Methods of a Dividend Schedule
+------------------------------
+*/
+
+/*
+ToDivList converts the dividend schedule to a discrete dividend list for
+the valuation date vd. The time to each dividend is the year fraction from
+vd to its ex-date under the day-count convention yf, and the amount of a
+YieldDividend is converted to cash at the spot price s. Dividends that go
+ex on or before vd are dropped, and the list is sorted by the times to
+the dividends. It returns the error ErrInvalidDividend if a dividend is
+paid before it goes ex, and the error ErrCurrencyMismatch if a dividend is
+paid in a currency other than ccy (a dividend with no currency is taken to
+be paid in ccy, and the currencies are not checked if ccy is empty);
+otherwise, it returns nil.
+
+Usage (example):
+var dl, e = ds.ToDivList(vd, 100.0, "USD", yf)
+
+where ds is of the type equity.DivSchedule.
+*/
+func (ds DivSchedule) ToDivList(vd time.Time, s float64, ccy string, yf YearFraction) (DivList, error) {
+	dated := make(DivSchedule, 0, len(ds))
+	for _, d := range ds {
+		if d.PayDate.Before(d.ExDate) {
+			return DivList(nil), ErrInvalidDividend("Dividend is paid before it goes ex.")
+		}
+		if ccy != "" && d.Currency != "" && d.Currency != ccy {
+			return DivList(nil), ErrCurrencyMismatch(fmt.Sprintf("Dividend is paid in %s instead of %s.", d.Currency, ccy))
+		}
+		if d.ExDate.After(vd) {
+			dated = append(dated, d)
+		}
+	}
+	sort.Stable(byExDate(dated))
+	dl := make(DivList, 0, len(dated))
+	for _, d := range dated {
+		amount := d.Amount
+		if d.Kind == YieldDividend {
+			amount *= s
+		}
+		dl = dl.AddDiv(yf(vd, d.ExDate), amount)
+	}
+	return dl, nil
+}
+
+/*
+byExDate is an unexported type that sorts dated dividends by their
+ex-dates.
+*/
+type byExDate DivSchedule
+
+func (ds byExDate) Len() int           { return len(ds) }
+func (ds byExDate) Less(i, j int) bool { return ds[i].ExDate.Before(ds[j].ExDate) }
+func (ds byExDate) Swap(i, j int)      { ds[i], ds[j] = ds[j], ds[i] }
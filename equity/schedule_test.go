@@ -0,0 +1,94 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package equity
+
+import (
+	"testing"
+	"time"
+)
+
+/*
+act365 is the Actual/365 Fixed day-count convention used by the test cases.
+*/
+func act365(start time.Time, end time.Time) float64 {
+	return end.Sub(start).Hours() / 24.0 / 365.0
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestToDivList(t *testing.T) {
+	vd := date(2016, 6, 30)
+	ds := DivSchedule{
+		{ExDate: date(2016, 12, 1), PayDate: date(2016, 12, 15), Amount: 0.01, Currency: "USD", Kind: YieldDividend},
+		{ExDate: date(2016, 6, 30), PayDate: date(2016, 7, 15), Amount: 3.0, Currency: "USD", Kind: CashDividend},
+		{ExDate: date(2016, 9, 1), PayDate: date(2016, 9, 15), Amount: 2.0, Currency: "USD", Kind: CashDividend},
+		{ExDate: date(2016, 3, 1), PayDate: date(2016, 3, 15), Amount: 2.0, Currency: "USD", Kind: CashDividend},
+		{ExDate: date(2016, 10, 3), PayDate: date(2016, 10, 3), Amount: 5.0, Kind: SpecialDividend},
+	}
+	dl, err := ds.ToDivList(vd, 80.0, "USD", act365)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The dividends that go ex on or before the valuation date are dropped,
+	// and the others are sorted by their ex-dates; the yield dividend is
+	// converted to cash at the spot price.
+	want := DivList{
+		{act365(vd, date(2016, 9, 1)), 2.0},
+		{act365(vd, date(2016, 10, 3)), 5.0},
+		{act365(vd, date(2016, 12, 1)), 0.8},
+	}
+	if len(dl) != len(want) {
+		t.Fatalf("ToDivList = %v, want %v", dl, want)
+	}
+	for i := range want {
+		if dl[i] != want[i] {
+			t.Errorf("ToDivList()[%d] = %v, want %v", i, dl[i], want[i])
+		}
+	}
+}
+
+func TestToDivListErrors(t *testing.T) {
+	vd := date(2016, 6, 30)
+	eur := DivSchedule{{ExDate: date(2016, 9, 1), PayDate: date(2016, 9, 15), Amount: 2.0, Currency: "EUR"}}
+	if _, err := eur.ToDivList(vd, 100.0, "USD", act365); err == nil {
+		t.Error("ToDivList of a EUR dividend in USD returned no error")
+	} else if _, ok := err.(ErrCurrencyMismatch); !ok {
+		t.Errorf("ToDivList of a EUR dividend in USD returned %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := eur.ToDivList(vd, 100.0, "EUR", act365); err != nil {
+		t.Errorf("ToDivList of a EUR dividend in EUR returned %v", err)
+	}
+	// A dividend that is already ex is still checked.
+	early := DivSchedule{{ExDate: date(2016, 3, 15), PayDate: date(2016, 3, 1), Amount: 2.0}}
+	if _, err := early.ToDivList(vd, 100.0, "USD", act365); err == nil {
+		t.Error("ToDivList of a dividend paid before it goes ex returned no error")
+	} else if _, ok := err.(ErrInvalidDividend); !ok {
+		t.Errorf("ToDivList of a dividend paid before it goes ex returned %v, want ErrInvalidDividend", err)
+	}
+}
@@ -30,7 +30,48 @@ Quantstruct Library.
 */
 package math
 
-import "github.com/datastream/probab/dst"
+import (
+	"fmt"
+	"github.com/datastream/probab/dst"
+	"math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrSingularMatrix is returned when a system of linear equations
+does not have a unique solution.
+*/
+type ErrSingularMatrix string
+
+func (e ErrSingularMatrix) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+The error ErrRootNotFound is returned when a root finder is not given a
+bracketing interval, or fails to converge within its maximum number of
+iterations.
+*/
+type ErrRootNotFound string
+
+func (e ErrRootNotFound) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+The error ErrNotConverged is returned when an optimizer fails to converge
+within its maximum number of iterations, or stalls before it converges.
+*/
+type ErrNotConverged string
+
+func (e ErrNotConverged) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
 
 /*
 =================
@@ -53,3 +94,494 @@ Normal Distribution at x.
 func PDF(x float64) float64 {
 	return dst.NormalPDFAt(0.0, 1.0, x)
 }
+
+/*
+==============================
+Bivariate Normal Distribution
+==============================
+*/
+
+/*
+Abscissae and weights of the 6-point, 12-point and 20-point Gauss-Legendre
+quadrature rules (one half of each symmetric rule) used by BivariateCDF.
+*/
+var (
+	glAbscissae = [3][]float64{
+		{-0.9324695142031522, -0.6612093864662647, -0.2386191860831970},
+		{-0.9815606342467191, -0.9041172563704750, -0.7699026741943050,
+			-0.5873179542866171, -0.3678314989981802, -0.1252334085114692},
+		{-0.9931285991850949, -0.9639719272779138, -0.9122344282513259,
+			-0.8391169718222188, -0.7463319064601508, -0.6360536807265150,
+			-0.5108670019508271, -0.3737060887154196, -0.2277858511416451,
+			-0.07652652113349733},
+	}
+	glWeights = [3][]float64{
+		{0.1713244923791705, 0.3607615730481384, 0.4679139345726904},
+		{0.04717533638651177, 0.1069393259953183, 0.1600783285433464,
+			0.2031674267230659, 0.2334925365383547, 0.2491470458134029},
+		{0.01761400713915212, 0.04060142980038694, 0.06267204833410906,
+			0.08327674157670475, 0.1019301198172404, 0.1181945319615184,
+			0.1316886384491766, 0.1420961093183821, 0.1491729864726037,
+			0.1527533871307259},
+	}
+)
+
+/*
+BivariateCDF returns the Cumulative Distribution Function of the standard
+Bivariate Normal Distribution with correlation rho at (x, y), i.e. the
+probability that X < x and Y < y. It implements the algorithm of Genz
+(2004), which is accurate to about 15 decimal places.
+*/
+func BivariateCDF(x float64, y float64, rho float64) float64 {
+	// Genz's algorithm computes the upper tail probability P(X > h, Y > k).
+	h, k := -x, -y
+	hk := h * k
+	n := 2
+	switch {
+	case math.Abs(rho) < 0.3:
+		n = 0
+	case math.Abs(rho) < 0.75:
+		n = 1
+	}
+	xs, ws := glAbscissae[n], glWeights[n]
+	bvn := 0.0
+	if math.Abs(rho) < 0.925 {
+		if rho != 0.0 {
+			hs := (h*h + k*k) / 2.0
+			asr := math.Asin(rho)
+			for i := range xs {
+				for _, sign := range [2]float64{-1.0, 1.0} {
+					sn := math.Sin(asr * (sign*xs[i] + 1.0) / 2.0)
+					bvn += ws[i] * math.Exp((sn*hk-hs)/(1.0-sn*sn))
+				}
+			}
+			bvn = bvn * asr / (4.0 * math.Pi)
+		}
+		return bvn + CDF(-h)*CDF(-k)
+	}
+	if rho < 0.0 {
+		k, hk = -k, -hk
+	}
+	if math.Abs(rho) < 1.0 {
+		as := (1.0 - rho) * (1.0 + rho)
+		a := math.Sqrt(as)
+		bs := (h - k) * (h - k)
+		c := (4.0 - hk) / 8.0
+		d := (12.0 - hk) / 16.0
+		asr := -(bs/as + hk) / 2.0
+		if asr > -100.0 {
+			bvn = a * math.Exp(asr) * (1.0 - c*(bs-as)*(1.0-d*bs/5.0)/3.0 + c*d*as*as/5.0)
+		}
+		if -hk < 100.0 {
+			b := math.Sqrt(bs)
+			bvn -= math.Exp(-hk/2.0) * math.Sqrt(2.0*math.Pi) * CDF(-b/a) * b * (1.0 - c*bs*(1.0-d*bs/5.0)/3.0)
+		}
+		a = a / 2.0
+		for i := range xs {
+			for _, sign := range [2]float64{-1.0, 1.0} {
+				xi := a * (sign*xs[i] + 1.0)
+				xi = xi * xi
+				rs := math.Sqrt(1.0 - xi)
+				asr := -(bs/xi + hk) / 2.0
+				if asr > -100.0 {
+					bvn += a * ws[i] * math.Exp(asr) *
+						(math.Exp(-hk*(1.0-rs)/(2.0*(1.0+rs)))/rs - (1.0 + c*xi*(1.0+d*xi)))
+				}
+			}
+		}
+		bvn = -bvn / (2.0 * math.Pi)
+	}
+	if rho > 0.0 {
+		return bvn + CDF(-math.Max(h, k))
+	}
+	bvn = -bvn
+	if k > h {
+		if h < 0.0 {
+			bvn += CDF(k) - CDF(h)
+		} else {
+			bvn += CDF(-h) - CDF(-k)
+		}
+	}
+	return bvn
+}
+
+/*
+==============
+Linear Algebra
+==============
+*/
+
+/*
+SolveLinear returns the solution x of the system of linear equations a x = y,
+where a is a square matrix given as a slice of rows, using Gaussian
+elimination with partial pivoting. The arguments are not modified. It
+returns the error ErrSingularMatrix if a is singular.
+
+Usage (example):
+var x, e = math.SolveLinear([][]float64{{2.0, 1.0}, {1.0, 3.0}}, []float64{3.0, 5.0})
+*/
+func SolveLinear(a [][]float64, y []float64) ([]float64, error) {
+	n := len(y)
+	// Work on an augmented copy of the system.
+	m := make([][]float64, n)
+	for i := range m {
+		if len(a[i]) != n {
+			return nil, ErrSingularMatrix("Matrix is not square.")
+		}
+		m[i] = append(append(make([]float64, 0, n+1), a[i]...), y[i])
+	}
+	for c := 0; c < n; c++ {
+		pivot := c
+		for i := c + 1; i < n; i++ {
+			if math.Abs(m[i][c]) > math.Abs(m[pivot][c]) {
+				pivot = i
+			}
+		}
+		if m[pivot][c] == 0.0 || math.IsNaN(m[pivot][c]) {
+			return nil, ErrSingularMatrix("Matrix is singular.")
+		}
+		m[c], m[pivot] = m[pivot], m[c]
+		for i := c + 1; i < n; i++ {
+			f := m[i][c] / m[c][c]
+			for j := c; j <= n; j++ {
+				m[i][j] -= f * m[c][j]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := m[i][n]
+		for j := i + 1; j < n; j++ {
+			sum -= m[i][j] * x[j]
+		}
+		x[i] = sum / m[i][i]
+	}
+	return x, nil
+}
+
+/*
+SolveTridiagonal returns the solution x of the tridiagonal system of linear
+equations a[i] x[i-1] + b[i] x[i] + c[i] x[i+1] = d[i], i = 0, ..., n-1,
+using the Thomas algorithm; a[0] and c[n-1] are not used. The arguments are
+not modified. It returns the error ErrSingularMatrix if a zero pivot is
+encountered.
+
+Usage (example):
+var x, e = math.SolveTridiagonal([]float64{0.0, 1.0}, []float64{2.0, 3.0}, []float64{1.0, 0.0}, []float64{3.0, 5.0})
+*/
+func SolveTridiagonal(a []float64, b []float64, c []float64, d []float64) ([]float64, error) {
+	n := len(d)
+	cp := make([]float64, n)
+	x := make([]float64, n)
+	// Forward sweep, storing the modified right-hand side in x.
+	for i := 0; i < n; i++ {
+		pivot := b[i]
+		if i > 0 {
+			pivot -= a[i] * cp[i-1]
+		}
+		if pivot == 0.0 || math.IsNaN(pivot) {
+			return nil, ErrSingularMatrix("Matrix is singular.")
+		}
+		if i < n-1 {
+			cp[i] = c[i] / pivot
+		}
+		x[i] = d[i]
+		if i > 0 {
+			x[i] -= a[i] * x[i-1]
+		}
+		x[i] /= pivot
+	}
+	// Back substitution.
+	for i := n - 2; i >= 0; i-- {
+		x[i] -= cp[i] * x[i+1]
+	}
+	return x, nil
+}
+
+/*
+============
+Root Finding
+============
+*/
+
+/*
+Brent returns a root of the function f in the interval [a, b], to within
+the tolerance tol on x, using Brent's method, which combines bisection
+with the secant method and inverse quadratic interpolation. It returns
+the error ErrRootNotFound if f(a) and f(b) have the same sign, or if no
+root is found within maxIter iterations.
+
+Usage (example):
+var x, e = math.Brent(func(x float64) float64 { return x*x - 2.0 }, 0.0, 2.0, 1.0e-12, 100)
+*/
+func Brent(f func(float64) float64, a float64, b float64, tol float64, maxIter int) (float64, error) {
+	fa, fb := f(a), f(b)
+	if fa == 0.0 {
+		return a, nil
+	}
+	if fb == 0.0 {
+		return b, nil
+	}
+	if math.IsNaN(fa) || math.IsNaN(fb) || (fa > 0.0) == (fb > 0.0) {
+		return math.NaN(), ErrRootNotFound("Interval does not bracket a root.")
+	}
+	// b is the best estimate so far, a the previous one, and c the contrapoint.
+	c, fc := a, fa
+	d := b - a
+	e := d
+	for n := 0; n < maxIter; n++ {
+		if (fb > 0.0) == (fc > 0.0) {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		tol1 := 2.0*2.2e-16*math.Abs(b) + 0.5*tol
+		m := 0.5 * (c - b)
+		if math.Abs(m) <= tol1 || fb == 0.0 {
+			return b, nil
+		}
+		if math.Abs(e) >= tol1 && math.Abs(fa) > math.Abs(fb) {
+			// Try inverse quadratic interpolation, or the secant method.
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				p = 2.0 * m * s
+				q = 1.0 - s
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = s * (2.0*m*q*(q-r) - (b-a)*(r-1.0))
+				q = (q - 1.0) * (r - 1.0) * (s - 1.0)
+			}
+			if p > 0.0 {
+				q = -q
+			} else {
+				p = -p
+			}
+			if 2.0*p < math.Min(3.0*m*q-math.Abs(tol1*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				d = m
+				e = d
+			}
+		} else {
+			d = m
+			e = d
+		}
+		a, fa = b, fb
+		if math.Abs(d) > tol1 {
+			b += d
+		} else if m > 0.0 {
+			b += tol1
+		} else {
+			b -= tol1
+		}
+		fb = f(b)
+	}
+	return math.NaN(), ErrRootNotFound("Root finder did not converge.")
+}
+
+/*
+=====================
+Numerical Integration
+=====================
+*/
+
+/*
+GaussLaguerre returns the abscissae x and weights w of the n-point
+Gauss-Laguerre quadrature rule, so that the integral of Exp(-x) f(x) from 0
+to infinity is approximately the sum of w[i] f(x[i]). The abscissae are
+found by Newton's method on the Laguerre polynomial of degree n, and are
+returned in increasing order; the smallest weights underflow to zero when
+n is above about 180. It returns empty slices if n is not positive.
+
+Usage (example):
+var x, w = math.GaussLaguerre(64)
+*/
+func GaussLaguerre(n int) ([]float64, []float64) {
+	if n < 1 {
+		return []float64{}, []float64{}
+	}
+	x, w := make([]float64, n), make([]float64, n)
+	var z float64
+	for i := 0; i < n; i++ {
+		// Initial guesses of the roots, after Stroud and Secrest (1966).
+		switch i {
+		case 0:
+			z = 3.0 / (1.0 + 2.4*float64(n))
+		case 1:
+			z += 15.0 / (1.0 + 2.5*float64(n))
+		default:
+			ai := float64(i - 1)
+			z += ((1.0 + 2.55*ai) / (1.9 * ai)) * (z - x[i-2])
+		}
+		var p1, p2, pp float64
+		for iter := 0; iter < 100; iter++ {
+			// Evaluate the Laguerre polynomials of degree n and n-1 at z by
+			// their recurrence relation, and the derivative of the former.
+			p1, p2 = 1.0, 0.0
+			for j := 1; j <= n; j++ {
+				p3 := p2
+				p2 = p1
+				p1 = ((float64(2*j-1)-z)*p2 - float64(j-1)*p3) / float64(j)
+			}
+			pp = float64(n) * (p1 - p2) / z
+			z1 := z
+			z = z1 - p1/pp
+			if math.Abs(z-z1) <= 1.0e-14*math.Abs(z) {
+				break
+			}
+		}
+		x[i] = z
+		w[i] = -1.0 / (pp * float64(n) * p2)
+	}
+	return x, w
+}
+
+/*
+============
+Optimization
+============
+*/
+
+/*
+LevenbergMarquardt returns the parameters x, within the bounds lower and
+upper, that minimize the sum of the squares of the residuals f(x), using
+the Levenberg-Marquardt method from the initial guess x0. The Jacobian of f
+is computed by forward differences, stepping away from the nearer bound;
+the parameters at a bound that the step would cross are held there, and
+each step is projected onto the bounds. The iteration stops when the
+sum of squares or the parameters change by less than the relative
+tolerance tol. The result is deterministic for given arguments. It
+returns the best parameters found, together with the error
+ErrNotConverged if the iteration does not stop within maxIter iterations
+or if no step reduces the sum of squares before the damping exceeds 1e16,
+or the error ErrSingularMatrix if the slices x0, lower and upper are not
+all of the same length.
+
+Usage (example):
+var x, e = math.LevenbergMarquardt(f, []float64{1.0, 1.0}, []float64{0.0, 0.0}, []float64{10.0, 10.0}, 1.0e-10, 100)
+*/
+func LevenbergMarquardt(f func([]float64) []float64, x0 []float64, lower []float64, upper []float64, tol float64, maxIter int) ([]float64, error) {
+	n := len(x0)
+	if len(lower) != n || len(upper) != n {
+		return nil, ErrSingularMatrix("Parameters and bounds are not of the same length.")
+	}
+	project := func(x []float64) []float64 {
+		for j := range x {
+			x[j] = math.Max(lower[j], math.Min(upper[j], x[j]))
+		}
+		return x
+	}
+	sumSquares := func(r []float64) float64 {
+		var s float64
+		for _, e := range r {
+			s += e * e
+		}
+		return s
+	}
+	x := project(append([]float64{}, x0...))
+	r := f(x)
+	cost := sumSquares(r)
+	lambda := 1.0e-3
+	for iter := 0; iter < maxIter; iter++ {
+		if cost == 0.0 {
+			return x, nil
+		}
+		// Jacobian by forward differences, j[i][k] = d r[i] / d x[k].
+		jac := make([][]float64, len(r))
+		for i := range jac {
+			jac[i] = make([]float64, n)
+		}
+		for k := 0; k < n; k++ {
+			// A parameter with equal bounds is held fixed.
+			if lower[k] == upper[k] {
+				continue
+			}
+			h := 1.0e-7 * math.Max(math.Abs(x[k]), 1.0)
+			if x[k]+h > upper[k] {
+				h = -h
+			}
+			xh := append([]float64{}, x...)
+			xh[k] += h
+			rh := f(xh)
+			for i := range r {
+				jac[i][k] = (rh[i] - r[i]) / h
+			}
+		}
+		// Normal equations (J'J) dx = -J'r.
+		jtj, jtr := make([][]float64, n), make([]float64, n)
+		for k := 0; k < n; k++ {
+			jtj[k] = make([]float64, n)
+			for l := 0; l < n; l++ {
+				for i := range r {
+					jtj[k][l] += jac[i][k] * jac[i][l]
+				}
+			}
+			for i := range r {
+				jtr[k] -= jac[i][k] * r[i]
+			}
+		}
+		// A parameter at a bound that the descent direction points beyond is
+		// held there for this step, so that projected steps do not stall.
+		active := make([]bool, n)
+		for k := range active {
+			active[k] = (x[k] <= lower[k] && jtr[k] <= 0.0) || (x[k] >= upper[k] && jtr[k] >= 0.0)
+		}
+		// Raise the damping until a step reduces the sum of squares.
+		for {
+			if lambda > 1.0e16 {
+				return x, ErrNotConverged("No step reduces the sum of squares.")
+			}
+			a, y := make([][]float64, n), make([]float64, n)
+			for k := range a {
+				a[k] = make([]float64, n)
+				if active[k] {
+					a[k][k] = 1.0
+					continue
+				}
+				for l := range a[k] {
+					if !active[l] {
+						a[k][l] = jtj[k][l]
+					}
+				}
+				a[k][k] += lambda * math.Max(jtj[k][k], 1.0e-12)
+				y[k] = jtr[k]
+			}
+			dx, err := SolveLinear(a, y)
+			if err != nil {
+				lambda *= 10.0
+				continue
+			}
+			xn := make([]float64, n)
+			for k := range xn {
+				xn[k] = x[k] + dx[k]
+			}
+			xn = project(xn)
+			rn := f(xn)
+			costn := sumSquares(rn)
+			if math.IsNaN(costn) || costn >= cost {
+				lambda *= 10.0
+				continue
+			}
+			var step, size float64
+			for k := range xn {
+				step += (xn[k] - x[k]) * (xn[k] - x[k])
+				size += x[k] * x[k]
+			}
+			converged := cost-costn <= tol*cost || math.Sqrt(step) <= tol*(math.Sqrt(size)+tol)
+			x, r, cost = xn, rn, costn
+			lambda = math.Max(lambda/10.0, 1.0e-12)
+			if converged {
+				return x, nil
+			}
+			break
+		}
+	}
+	return x, ErrNotConverged("Optimizer did not converge.")
+}
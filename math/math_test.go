@@ -0,0 +1,60 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package math
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevenbergMarquardt(t *testing.T) {
+	// The residuals vanish at x = (1, 2), which lies inside the bounds.
+	f := func(x []float64) []float64 {
+		return []float64{x[0] - 1.0, 10.0 * (x[1] - x[0]*x[0] - 1.0)}
+	}
+	x, err := LevenbergMarquardt(f, []float64{3.0, 0.0}, []float64{0.0, 0.0}, []float64{5.0, 5.0}, 1.0e-12, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(x[0]-1.0) > 1.0e-6 || math.Abs(x[1]-2.0) > 1.0e-6 {
+		t.Errorf("LevenbergMarquardt = %v, want [1 2]", x)
+	}
+}
+
+func TestLevenbergMarquardtStalls(t *testing.T) {
+	// No step changes the residuals, so the damping grows without bound.
+	f := func(x []float64) []float64 {
+		return []float64{1.0}
+	}
+	x, err := LevenbergMarquardt(f, []float64{0.5}, []float64{0.0}, []float64{1.0}, 1.0e-12, 100)
+	if _, ok := err.(ErrNotConverged); !ok {
+		t.Errorf("LevenbergMarquardt with constant residuals returned %v, want ErrNotConverged", err)
+	}
+	if len(x) != 1 || x[0] != 0.5 {
+		t.Errorf("LevenbergMarquardt with constant residuals = %v, want the initial guess [0.5]", x)
+	}
+}
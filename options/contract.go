@@ -0,0 +1,143 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package options
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidOption is returned when the terms of an option contract
+are invalid.
+*/
+type ErrInvalidOption string
+
+func (e ErrInvalidOption) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+===============
+Option Contract
+===============
+*/
+
+/*
+Settlement enumerates how a financial option is settled on exercise;
+either by a payment of its intrinsic value in cash (CashSettled), or by the
+delivery of the underlying instrument against the strike price
+(PhysicallySettled).
+*/
+type Settlement int
+
+const (
+	CashSettled Settlement = iota
+	PhysicallySettled
+)
+
+/*
+Option represents the terms of a financial option contract. The exercise
+dates are only used by a Bermudan option, and the contract multiplier is
+the number of units of the underlying instrument that one contract is
+written on.
+
+Usage (example):
+var o = options.Option{
+	Underlying: "XYZ",
+	Type:       options.Call,
+	Strike:     100.0,
+	Expiry:     time.Date(2016, 12, 16, 0, 0, 0, 0, time.UTC),
+	Exercise:   options.American,
+	Settlement: options.PhysicallySettled,
+	Multiplier: 100.0,
+	Currency:   "USD",
+}
+*/
+type Option struct {
+	Underlying    string
+	Type          OptionType
+	Strike        float64
+	Expiry        time.Time
+	Exercise      ExerciseStyle
+	ExerciseDates []time.Time // for a Bermudan option
+	Settlement    Settlement
+	Multiplier    float64
+	Currency      string
+}
+
+/*
+Validate returns the error ErrInvalidOption if the terms of the option
+contract are invalid, i.e. if it has no underlying instrument, expiry date
+or currency, if its type, exercise style or settlement is unknown, if its
+strike price or multiplier is not positive, or if it is a Bermudan option
+without exercise dates on or before the expiry date; otherwise, it returns
+nil.
+
+Usage (example):
+var e = o.Validate()
+
+where o is of the type options.Option.
+*/
+func (o Option) Validate() error {
+	switch {
+	case o.Underlying == "":
+		return ErrInvalidOption("Option has no underlying instrument.")
+	case o.Type != Call && o.Type != Put:
+		return ErrInvalidOption("Option type is unknown.")
+	case !(o.Strike > 0.0) || math.IsInf(o.Strike, 0):
+		return ErrInvalidOption("Strike price must be positive.")
+	case o.Expiry.IsZero():
+		return ErrInvalidOption("Option has no expiry date.")
+	case o.Exercise != European && o.Exercise != American && o.Exercise != Bermudan:
+		return ErrInvalidOption("Exercise style is unknown.")
+	case o.Settlement != CashSettled && o.Settlement != PhysicallySettled:
+		return ErrInvalidOption("Settlement is unknown.")
+	case !(o.Multiplier > 0.0) || math.IsInf(o.Multiplier, 0):
+		return ErrInvalidOption("Contract multiplier must be positive.")
+	case o.Currency == "":
+		return ErrInvalidOption("Option has no currency.")
+	}
+	if o.Exercise == Bermudan {
+		if len(o.ExerciseDates) == 0 {
+			return ErrInvalidOption("Bermudan option has no exercise dates.")
+		}
+		for _, d := range o.ExerciseDates {
+			if d.After(o.Expiry) {
+				return ErrInvalidOption("Exercise date is after the expiry date.")
+			}
+		}
+	}
+	return nil
+}
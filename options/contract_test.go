@@ -0,0 +1,89 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package options
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func validOption() Option {
+	return Option{
+		Underlying: "XYZ",
+		Type:       Call,
+		Strike:     100.0,
+		Expiry:     time.Date(2016, 12, 16, 0, 0, 0, 0, time.UTC),
+		Exercise:   American,
+		Settlement: PhysicallySettled,
+		Multiplier: 100.0,
+		Currency:   "USD",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validOption().Validate(); err != nil {
+		t.Fatalf("Validate returned %v", err)
+	}
+	expiry := validOption().Expiry
+	tests := []struct {
+		name   string
+		modify func(o *Option)
+	}{
+		{"no underlying", func(o *Option) { o.Underlying = "" }},
+		{"unknown type", func(o *Option) { o.Type = OptionType(42) }},
+		{"zero strike", func(o *Option) { o.Strike = 0.0 }},
+		{"negative strike", func(o *Option) { o.Strike = -100.0 }},
+		{"NaN strike", func(o *Option) { o.Strike = math.NaN() }},
+		{"infinite strike", func(o *Option) { o.Strike = math.Inf(1) }},
+		{"no expiry", func(o *Option) { o.Expiry = time.Time{} }},
+		{"unknown exercise", func(o *Option) { o.Exercise = ExerciseStyle(42) }},
+		{"unknown settlement", func(o *Option) { o.Settlement = Settlement(42) }},
+		{"zero multiplier", func(o *Option) { o.Multiplier = 0.0 }},
+		{"NaN multiplier", func(o *Option) { o.Multiplier = math.NaN() }},
+		{"no currency", func(o *Option) { o.Currency = "" }},
+		{"Bermudan without dates", func(o *Option) { o.Exercise = Bermudan }},
+		{"Bermudan date after expiry", func(o *Option) {
+			o.Exercise = Bermudan
+			o.ExerciseDates = []time.Time{expiry.AddDate(0, -3, 0), expiry.AddDate(0, 0, 1)}
+		}},
+	}
+	for _, tc := range tests {
+		o := validOption()
+		tc.modify(&o)
+		err := o.Validate()
+		if _, ok := err.(ErrInvalidOption); !ok {
+			t.Errorf("%s: Validate returned %v, want ErrInvalidOption", tc.name, err)
+		}
+	}
+	o := validOption()
+	o.Exercise = Bermudan
+	o.ExerciseDates = []time.Time{expiry.AddDate(0, -3, 0), expiry}
+	if err := o.Validate(); err != nil {
+		t.Errorf("Validate of a Bermudan option returned %v", err)
+	}
+}
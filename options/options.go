@@ -26,6 +26,12 @@ SOFTWARE.
 
 /*
 Package options provides the representations of financial options.
+
+This is a multi-file package and is made up of the following source files:
+  options.go  provides the enumerations of the types and exercise styles of
+              financial options;
+  contract.go provides the terms of a financial option contract;
+  payoff.go   provides the payoffs of financial options.
 */
 package options
 
@@ -45,3 +51,36 @@ const (
 	Call OptionType = iota
 	Put
 )
+
+/*
+ExerciseStyle enumerates a financial option's exercise style; a financial
+option can be exercised either only at expiry (European), at any time up
+to expiry (American), or on a schedule of dates up to expiry (Bermudan).
+*/
+type ExerciseStyle int
+
+const (
+	European ExerciseStyle = iota
+	American
+	Bermudan
+)
+
+/*
+==============
+Barrier Option
+==============
+*/
+
+/*
+BarrierType enumerates the type of a single-barrier option; the barrier
+can lie either below (Down) or above (Up) the spot price, and touching it
+either activates (In) or extinguishes (Out) the option.
+*/
+type BarrierType int
+
+const (
+	DownAndIn BarrierType = iota
+	UpAndIn
+	DownAndOut
+	UpAndOut
+)
@@ -0,0 +1,433 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package options
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidPayoff is returned when the terms of a payoff are
+invalid.
+*/
+type ErrInvalidPayoff string
+
+func (e ErrInvalidPayoff) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+=======
+Payoffs
+=======
+*/
+
+/*
+Payoff is the interface that wraps the methods that evaluate the payoff of
+a financial option at expiry.
+
+Terminal returns the payoff given the spot price s of the underlying
+instrument at expiry.
+
+Path returns the payoff given the path of spot prices of the underlying
+instrument from the valuation date to expiry; the last element of the path
+is the spot price at expiry. The payoffs in this package depend only on
+the spot price at expiry, and return the same value from Path as from
+Terminal.
+
+Validate returns an error if the terms of the payoff are invalid, e.g. an
+option type that is neither Call nor Put; the pricers validate a payoff
+before evaluating it, since Terminal and Path cannot return an error.
+*/
+type Payoff interface {
+	Terminal(s float64) float64
+	Path(path []float64) float64
+	Validate() error
+}
+
+/*
+last is an unexported function that returns the last element of a path.
+*/
+func last(path []float64) float64 {
+	return path[len(path)-1]
+}
+
+/*
+checkType is an unexported function that returns the error
+ErrInvalidPayoff if the option type of a payoff is neither Call nor Put;
+otherwise, it returns nil.
+*/
+func checkType(ot OptionType) error {
+	if ot != Call && ot != Put {
+		return ErrInvalidPayoff("Option type is unknown.")
+	}
+	return nil
+}
+
+/*
+Vanilla represents the payoff of a call or put option.
+
+Usage (example):
+var p = options.Vanilla{options.Call, 100.0}
+*/
+type Vanilla struct {
+	Type   OptionType
+	Strike float64
+}
+
+/*
+Terminal returns max(s - Strike, 0) for a call, or max(Strike - s, 0) for a
+put.
+*/
+func (p Vanilla) Terminal(s float64) float64 {
+	if p.Type == Put {
+		return math.Max(p.Strike-s, 0.0)
+	}
+	return math.Max(s-p.Strike, 0.0)
+}
+
+/*
+Path returns the payoff at the last spot price of the path.
+*/
+func (p Vanilla) Path(path []float64) float64 {
+	return p.Terminal(last(path))
+}
+
+/*
+Validate returns the error ErrInvalidPayoff if the option type is neither
+Call nor Put.
+*/
+func (p Vanilla) Validate() error {
+	return checkType(p.Type)
+}
+
+/*
+CashOrNothing represents the payoff of a digital option that pays a fixed
+cash amount if it expires in the money.
+
+Usage (example):
+var p = options.CashOrNothing{options.Call, 100.0, 10.0}
+*/
+type CashOrNothing struct {
+	Type   OptionType
+	Strike float64
+	Cash   float64
+}
+
+/*
+Terminal returns Cash if s is above the strike price for a call (below it
+for a put), or zero otherwise.
+*/
+func (p CashOrNothing) Terminal(s float64) float64 {
+	if (p.Type == Put && s < p.Strike) || (p.Type != Put && s > p.Strike) {
+		return p.Cash
+	}
+	return 0.0
+}
+
+/*
+Path returns the payoff at the last spot price of the path.
+*/
+func (p CashOrNothing) Path(path []float64) float64 {
+	return p.Terminal(last(path))
+}
+
+/*
+Validate returns the error ErrInvalidPayoff if the option type is neither
+Call nor Put.
+*/
+func (p CashOrNothing) Validate() error {
+	return checkType(p.Type)
+}
+
+/*
+AssetOrNothing represents the payoff of a digital option that pays the
+spot price of the underlying instrument if it expires in the money.
+
+Usage (example):
+var p = options.AssetOrNothing{options.Call, 100.0}
+*/
+type AssetOrNothing struct {
+	Type   OptionType
+	Strike float64
+}
+
+/*
+Terminal returns s if s is above the strike price for a call (below it for
+a put), or zero otherwise.
+*/
+func (p AssetOrNothing) Terminal(s float64) float64 {
+	if (p.Type == Put && s < p.Strike) || (p.Type != Put && s > p.Strike) {
+		return s
+	}
+	return 0.0
+}
+
+/*
+Path returns the payoff at the last spot price of the path.
+*/
+func (p AssetOrNothing) Path(path []float64) float64 {
+	return p.Terminal(last(path))
+}
+
+/*
+Validate returns the error ErrInvalidPayoff if the option type is neither
+Call nor Put.
+*/
+func (p AssetOrNothing) Validate() error {
+	return checkType(p.Type)
+}
+
+/*
+Gap represents the payoff of a gap option, which pays s - Strike for a
+call (Strike - s for a put) if the spot price at expiry is above (below)
+the trigger price; the payoff can be negative.
+
+Usage (example):
+var p = options.Gap{options.Call, 100.0, 105.0}
+*/
+type Gap struct {
+	Type    OptionType
+	Strike  float64
+	Trigger float64
+}
+
+/*
+Terminal returns s - Strike if s is above the trigger price for a call, or
+Strike - s if s is below the trigger price for a put, and zero otherwise.
+*/
+func (p Gap) Terminal(s float64) float64 {
+	switch {
+	case p.Type == Put && s < p.Trigger:
+		return p.Strike - s
+	case p.Type != Put && s > p.Trigger:
+		return s - p.Strike
+	}
+	return 0.0
+}
+
+/*
+Path returns the payoff at the last spot price of the path.
+*/
+func (p Gap) Path(path []float64) float64 {
+	return p.Terminal(last(path))
+}
+
+/*
+Validate returns the error ErrInvalidPayoff if the option type is neither
+Call nor Put.
+*/
+func (p Gap) Validate() error {
+	return checkType(p.Type)
+}
+
+/*
+Power represents the payoff of a power option, which is the payoff of a
+call or put option on the spot price raised to the (positive) power
+Exponent.
+
+Usage (example):
+var p = options.Power{options.Call, 10000.0, 2.0}
+*/
+type Power struct {
+	Type     OptionType
+	Strike   float64
+	Exponent float64
+}
+
+/*
+Terminal returns max(s^Exponent - Strike, 0) for a call, or
+max(Strike - s^Exponent, 0) for a put.
+*/
+func (p Power) Terminal(s float64) float64 {
+	return Vanilla{p.Type, p.Strike}.Terminal(math.Pow(s, p.Exponent))
+}
+
+/*
+Path returns the payoff at the last spot price of the path.
+*/
+func (p Power) Path(path []float64) float64 {
+	return p.Terminal(last(path))
+}
+
+/*
+Validate returns the error ErrInvalidPayoff if the option type is neither
+Call nor Put, or if the exponent is not positive.
+*/
+func (p Power) Validate() error {
+	if err := checkType(p.Type); err != nil {
+		return err
+	}
+	if !(p.Exponent > 0.0) {
+		return ErrInvalidPayoff("Exponent must be positive.")
+	}
+	return nil
+}
+
+/*
+Capped represents a payoff that is capped at the amount Cap.
+
+Usage (example):
+var p = options.Capped{options.Vanilla{options.Call, 100.0}, 20.0}
+*/
+type Capped struct {
+	Payoff
+	Cap float64
+}
+
+/*
+Terminal returns the capped payoff at the spot price s.
+*/
+func (p Capped) Terminal(s float64) float64 {
+	return math.Min(p.Payoff.Terminal(s), p.Cap)
+}
+
+/*
+Path returns the capped payoff along the path.
+*/
+func (p Capped) Path(path []float64) float64 {
+	return math.Min(p.Payoff.Path(path), p.Cap)
+}
+
+/*
+Validate returns the error ErrInvalidPayoff if the capped payoff is nil, or
+otherwise the error returned by its Validate method.
+*/
+func (p Capped) Validate() error {
+	if p.Payoff == nil {
+		return ErrInvalidPayoff("Capped payoff has no payoff.")
+	}
+	return p.Payoff.Validate()
+}
+
+/*
+Floored represents a payoff that is floored at the amount Floor.
+
+Usage (example):
+var p = options.Floored{options.Vanilla{options.Call, 100.0}, 5.0}
+*/
+type Floored struct {
+	Payoff
+	Floor float64
+}
+
+/*
+Terminal returns the floored payoff at the spot price s.
+*/
+func (p Floored) Terminal(s float64) float64 {
+	return math.Max(p.Payoff.Terminal(s), p.Floor)
+}
+
+/*
+Path returns the floored payoff along the path.
+*/
+func (p Floored) Path(path []float64) float64 {
+	return math.Max(p.Payoff.Path(path), p.Floor)
+}
+
+/*
+Validate returns the error ErrInvalidPayoff if the floored payoff is nil,
+or otherwise the error returned by its Validate method.
+*/
+func (p Floored) Validate() error {
+	if p.Payoff == nil {
+		return ErrInvalidPayoff("Floored payoff has no payoff.")
+	}
+	return p.Payoff.Validate()
+}
+
+/*
+Straddle represents the payoff of a call and a put option with the same
+strike price.
+
+Usage (example):
+var p = options.Straddle{100.0}
+*/
+type Straddle struct {
+	Strike float64
+}
+
+/*
+Terminal returns |s - Strike|.
+*/
+func (p Straddle) Terminal(s float64) float64 {
+	return math.Abs(s - p.Strike)
+}
+
+/*
+Path returns the payoff at the last spot price of the path.
+*/
+func (p Straddle) Path(path []float64) float64 {
+	return p.Terminal(last(path))
+}
+
+/*
+Validate returns nil, since a straddle has no option type.
+*/
+func (p Straddle) Validate() error {
+	return nil
+}
+
+/*
+Strangle represents the payoff of a put option with the strike price
+PutStrike and a call option with the (higher) strike price CallStrike.
+
+Usage (example):
+var p = options.Strangle{95.0, 105.0}
+*/
+type Strangle struct {
+	PutStrike  float64
+	CallStrike float64
+}
+
+/*
+Terminal returns max(PutStrike - s, 0) + max(s - CallStrike, 0).
+*/
+func (p Strangle) Terminal(s float64) float64 {
+	return math.Max(p.PutStrike-s, 0.0) + math.Max(s-p.CallStrike, 0.0)
+}
+
+/*
+Path returns the payoff at the last spot price of the path.
+*/
+func (p Strangle) Path(path []float64) float64 {
+	return p.Terminal(last(path))
+}
+
+/*
+Validate returns nil, since a strangle has no option type.
+*/
+func (p Strangle) Validate() error {
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package options
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPayoffValidate(t *testing.T) {
+	valid := []Payoff{
+		Vanilla{Type: Put, Strike: 100.0},
+		CashOrNothing{Type: Call, Strike: 100.0, Cash: 10.0},
+		AssetOrNothing{Type: Put, Strike: 100.0},
+		Gap{Type: Call, Strike: 100.0, Trigger: 105.0},
+		Power{Type: Call, Strike: 10000.0, Exponent: 2.0},
+		Capped{Payoff: Vanilla{Type: Call, Strike: 100.0}, Cap: 20.0},
+		Floored{Payoff: Vanilla{Type: Put, Strike: 100.0}, Floor: 5.0},
+		Straddle{Strike: 100.0},
+		Strangle{PutStrike: 95.0, CallStrike: 105.0},
+	}
+	for _, p := range valid {
+		if err := p.Validate(); err != nil {
+			t.Errorf("%#v.Validate() = %v, want nil", p, err)
+		}
+	}
+	invalid := []Payoff{
+		Vanilla{Type: OptionType(2), Strike: 100.0},
+		CashOrNothing{Type: OptionType(-1), Strike: 100.0, Cash: 10.0},
+		AssetOrNothing{Type: OptionType(2), Strike: 100.0},
+		Gap{Type: OptionType(2), Strike: 100.0, Trigger: 105.0},
+		Power{Type: Call, Strike: 10000.0, Exponent: 0.0},
+		Capped{Payoff: Vanilla{Type: OptionType(2), Strike: 100.0}, Cap: 20.0},
+		Capped{Cap: 20.0},
+		Floored{Floor: 5.0},
+	}
+	for _, p := range invalid {
+		var e ErrInvalidPayoff
+		if err := p.Validate(); !errors.As(err, &e) {
+			t.Errorf("%#v.Validate() = %v, want ErrInvalidPayoff", p, err)
+		}
+	}
+}
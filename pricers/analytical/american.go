@@ -0,0 +1,400 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "github.com/kervinlow/quantstruct/math"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+)
+
+/*
+========================================================================
+Provides the analytical approximations for valuing American options on
+the generalized cost-of-carry b.
+========================================================================
+*/
+
+const (
+	bawTolerance     = 1.0e-10 // tolerance of the critical price, relative to the strike
+	bawMaxIterations = 500     // maximum number of iterations for the critical price
+)
+
+/*
+--------------------------------------------------------------------------
+BAW1987 -- Barone-Adesi and Whaley (1987) pricing model
+
+Description:
+A method that computes the theoretical value and greeks of an American
+option using the quadratic approximation of Barone-Adesi and Whaley, and
+saves the computed results in the fields of the ModelOutputs receiver.
+The greeks are computed numerically, and are scaled by the same market
+conventions as GBSM. It returns an *InputError if the inputs are invalid
+as for GBSM or if t or v is not positive, the error ErrNoConvergence if
+the critical price of the underlying instrument cannot be found, or an
+*OutputError if the value or a greek is not a finite number; otherwise,
+it returns nil.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.BAW1987(ot, s, k, t, v, r, b)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+v  volatility of the underlying instrument
+r  risk-free rate
+b  cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) BAW1987(ot OptionType, s float64, k float64, t float64, v float64, r float64, b float64) error {
+	in, err := checkGBSMInputs("BAW1987", ot, s, k, t, v, r, b)
+	if err != nil {
+		return err
+	}
+	if err := checkInputs("BAW1987", in, "t", "v"); err != nil {
+		return err
+	}
+	price := func(s, t, v, r, b float64) float64 {
+		value, e := getBAW1987Value(ot, s, k, t, v, r, b)
+		if e != nil {
+			err = e
+		}
+		return value
+	}
+	if e := out.numericalGreeks("BAW1987", price, s, t, v, r, b); e != nil {
+		return e
+	}
+	return err
+}
+
+/*
+getBAW1987Value is an unexported function that computes the theoretical
+value of an American option using the Barone-Adesi and Whaley (1987)
+pricing model.
+*/
+func getBAW1987Value(ot OptionType, s float64, k float64, t float64, v float64, r float64, b float64) (float64, error) {
+	var european ModelOutputs
+	european.GBSM(ot, s, k, t, v, r, b)
+	// Early exercise is never optimal for a call when b >= r, or for a put
+	// when r <= 0.
+	if (ot == Call && b >= r) || (ot == Put && r <= 0.0) {
+		return european.Value, nil
+	}
+	sk, err := getBAW1987Critical(ot, k, t, v, r, b)
+	if err != nil {
+		return NaN(), err
+	}
+	n := 2.0 * b / (v * v)
+	m := 2.0 * r / (v * v * (1.0 - Exp((-r)*t)))
+	d1 := (Log(sk/k) + (b+v*v/2.0)*t) / (v * Sqrt(t))
+	switch ot {
+	case Call:
+		q2 := (-(n - 1.0) + Sqrt((n-1.0)*(n-1.0)+4.0*m)) / 2.0
+		if s >= sk {
+			return s - k, nil
+		}
+		a2 := (sk / q2) * (1.0 - Exp((b-r)*t)*CDF(d1))
+		return european.Value + a2*Pow(s/sk, q2), nil
+	default:
+		q1 := (-(n - 1.0) - Sqrt((n-1.0)*(n-1.0)+4.0*m)) / 2.0
+		if s <= sk {
+			return k - s, nil
+		}
+		a1 := -(sk / q1) * (1.0 - Exp((b-r)*t)*CDF(-d1))
+		return european.Value + a1*Pow(s/sk, q1), nil
+	}
+}
+
+/*
+getBAW1987Critical is an unexported function that returns the critical
+price of the underlying instrument, above which an American call (or below
+which an American put) is exercised early, using the Newton-Raphson
+iteration of Barone-Adesi and Whaley (1987).
+*/
+func getBAW1987Critical(ot OptionType, k float64, t float64, v float64, r float64, b float64) (float64, error) {
+	n := 2.0 * b / (v * v)
+	m := 2.0 * r / (v * v)
+	mt := 2.0 * r / (v * v * (1.0 - Exp((-r)*t)))
+	vt := v * Sqrt(t)
+	carry := Exp((b - r) * t)
+	// Seed the iteration with the interpolation between the strike and the
+	// critical price of the perpetual option.
+	var si, q float64
+	switch ot {
+	case Call:
+		qu := (-(n - 1.0) + Sqrt((n-1.0)*(n-1.0)+4.0*m)) / 2.0
+		su := k / (1.0 - 1.0/qu)
+		h2 := -(b*t + 2.0*vt) * k / (su - k)
+		si = k + (su-k)*(1.0-Exp(h2))
+		q = (-(n - 1.0) + Sqrt((n-1.0)*(n-1.0)+4.0*mt)) / 2.0
+	case Put:
+		qu := (-(n - 1.0) - Sqrt((n-1.0)*(n-1.0)+4.0*m)) / 2.0
+		su := k / (1.0 - 1.0/qu)
+		h1 := (b*t - 2.0*vt) * k / (k - su)
+		si = su + (k-su)*Exp(h1)
+		q = (-(n - 1.0) - Sqrt((n-1.0)*(n-1.0)+4.0*mt)) / 2.0
+	}
+	for i := 0; i < bawMaxIterations; i++ {
+		var european ModelOutputs
+		european.GBSM(ot, si, k, t, v, r, b)
+		d1 := (Log(si/k) + (b+v*v/2.0)*t) / vt
+		var lhs, rhs, slope float64
+		switch ot {
+		case Call:
+			lhs = si - k
+			rhs = european.Value + (1.0-carry*CDF(d1))*si/q
+			slope = carry*CDF(d1)*(1.0-1.0/q) + (1.0-carry*PDF(d1)/vt)/q
+		case Put:
+			lhs = k - si
+			rhs = european.Value - (1.0-carry*CDF(-d1))*si/q
+			slope = -carry*CDF(-d1)*(1.0-1.0/q) - (1.0+carry*PDF(-d1)/vt)/q
+		}
+		if Abs(lhs-rhs)/k <= bawTolerance {
+			return si, nil
+		}
+		switch ot {
+		case Call:
+			si = (k + rhs - slope*si) / (1.0 - slope)
+		case Put:
+			si = (k - rhs + slope*si) / (1.0 + slope)
+		}
+		if IsNaN(si) || si <= 0.0 {
+			break
+		}
+	}
+	return NaN(), ErrNoConvergence("Critical price did not converge.")
+}
+
+/*
+--------------------------------------------------------------------------
+BJS1993 -- Bjerksund and Stensland (1993) pricing model
+
+Description:
+A method that computes the theoretical value and greeks of an American
+option using the flat exercise boundary approximation of Bjerksund and
+Stensland (1993), and saves the computed results in the fields of the
+ModelOutputs receiver. The greeks are computed numerically, and are
+scaled by the same market conventions as GBSM. It returns an *InputError
+if the inputs are invalid as for GBSM or if t or v is not positive, or an
+*OutputError if the value or a greek is not a finite number; otherwise, it
+returns nil.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.BJS1993(ot, s, k, t, v, r, b)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+v  volatility of the underlying instrument
+r  risk-free rate
+b  cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) BJS1993(ot OptionType, s float64, k float64, t float64, v float64, r float64, b float64) error {
+	in, err := checkGBSMInputs("BJS1993", ot, s, k, t, v, r, b)
+	if err != nil {
+		return err
+	}
+	if err := checkInputs("BJS1993", in, "t", "v"); err != nil {
+		return err
+	}
+	price := func(s, t, v, r, b float64) float64 {
+		// An American put is valued as a call by the put-call transformation.
+		if ot == Put {
+			return getBJS1993CallValue(k, s, t, v, r-b, -b)
+		}
+		return getBJS1993CallValue(s, k, t, v, r, b)
+	}
+	return out.numericalGreeks("BJS1993", price, s, t, v, r, b)
+}
+
+/*
+getBJS1993CallValue is an unexported function that computes the
+theoretical value of an American call using the Bjerksund and Stensland
+(1993) pricing model.
+*/
+func getBJS1993CallValue(s float64, k float64, t float64, v float64, r float64, b float64) float64 {
+	if b >= r {
+		var european ModelOutputs
+		european.GBSM(Call, s, k, t, v, r, b)
+		return european.Value
+	}
+	beta, bInf, b0 := getBJSBoundary(k, v, r, b)
+	ht := -(b*t + 2.0*v*Sqrt(t)) * b0 / (bInf - b0)
+	i := b0 + (bInf-b0)*(1.0-Exp(ht))
+	if s >= i {
+		return s - k
+	}
+	alpha := (i - k) * Pow(i, -beta)
+	return alpha*Pow(s, beta) -
+		alpha*bjsPhi(s, t, beta, i, i, v, r, b) +
+		bjsPhi(s, t, 1.0, i, i, v, r, b) -
+		bjsPhi(s, t, 1.0, k, i, v, r, b) -
+		k*bjsPhi(s, t, 0.0, i, i, v, r, b) +
+		k*bjsPhi(s, t, 0.0, k, i, v, r, b)
+}
+
+/*
+--------------------------------------------------------------------------
+BJS2002 -- Bjerksund and Stensland (2002) pricing model
+
+Description:
+A method that computes the theoretical value and greeks of an American
+option using the two-step exercise boundary approximation of Bjerksund
+and Stensland (2002), and saves the computed results in the fields of the
+ModelOutputs receiver. The greeks are computed numerically, and are
+scaled by the same market conventions as GBSM. It returns an *InputError
+if the inputs are invalid as for GBSM or if t or v is not positive, or an
+*OutputError if the value or a greek is not a finite number; otherwise, it
+returns nil.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.BJS2002(ot, s, k, t, v, r, b)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+v  volatility of the underlying instrument
+r  risk-free rate
+b  cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) BJS2002(ot OptionType, s float64, k float64, t float64, v float64, r float64, b float64) error {
+	in, err := checkGBSMInputs("BJS2002", ot, s, k, t, v, r, b)
+	if err != nil {
+		return err
+	}
+	if err := checkInputs("BJS2002", in, "t", "v"); err != nil {
+		return err
+	}
+	price := func(s, t, v, r, b float64) float64 {
+		// An American put is valued as a call by the put-call transformation.
+		if ot == Put {
+			return getBJS2002CallValue(k, s, t, v, r-b, -b)
+		}
+		return getBJS2002CallValue(s, k, t, v, r, b)
+	}
+	return out.numericalGreeks("BJS2002", price, s, t, v, r, b)
+}
+
+/*
+getBJS2002CallValue is an unexported function that computes the
+theoretical value of an American call using the Bjerksund and Stensland
+(2002) pricing model.
+*/
+func getBJS2002CallValue(s float64, k float64, t float64, v float64, r float64, b float64) float64 {
+	if b >= r {
+		var european ModelOutputs
+		european.GBSM(Call, s, k, t, v, r, b)
+		return european.Value
+	}
+	t1 := (Sqrt(5.0) - 1.0) / 2.0 * t
+	beta, bInf, b0 := getBJSBoundary(k, v, r, b)
+	ht1 := -(b*t1 + 2.0*v*Sqrt(t1)) * k * k / ((bInf - b0) * b0)
+	ht2 := -(b*t + 2.0*v*Sqrt(t)) * k * k / ((bInf - b0) * b0)
+	i1 := b0 + (bInf-b0)*(1.0-Exp(ht1))
+	i2 := b0 + (bInf-b0)*(1.0-Exp(ht2))
+	if s >= i2 {
+		return s - k
+	}
+	alpha1 := (i1 - k) * Pow(i1, -beta)
+	alpha2 := (i2 - k) * Pow(i2, -beta)
+	return alpha2*Pow(s, beta) -
+		alpha2*bjsPhi(s, t1, beta, i2, i2, v, r, b) +
+		bjsPhi(s, t1, 1.0, i2, i2, v, r, b) -
+		bjsPhi(s, t1, 1.0, i1, i2, v, r, b) -
+		k*bjsPhi(s, t1, 0.0, i2, i2, v, r, b) +
+		k*bjsPhi(s, t1, 0.0, i1, i2, v, r, b) +
+		alpha1*bjsPhi(s, t1, beta, i1, i2, v, r, b) -
+		alpha1*bjsPsi(s, t, beta, i1, i2, i1, t1, v, r, b) +
+		bjsPsi(s, t, 1.0, i1, i2, i1, t1, v, r, b) -
+		bjsPsi(s, t, 1.0, k, i2, i1, t1, v, r, b) -
+		k*bjsPsi(s, t, 0.0, i1, i2, i1, t1, v, r, b) +
+		k*bjsPsi(s, t, 0.0, k, i2, i1, t1, v, r, b)
+}
+
+/*
+getBJSBoundary is an unexported function that returns the parameters of
+the exercise boundary shared by the Bjerksund and Stensland models: beta,
+the critical price of the perpetual option, and the critical price at
+expiry.
+*/
+func getBJSBoundary(k float64, v float64, r float64, b float64) (float64, float64, float64) {
+	beta := (0.5 - b/(v*v)) + Sqrt((b/(v*v)-0.5)*(b/(v*v)-0.5)+2.0*r/(v*v))
+	bInf := beta / (beta - 1.0) * k
+	b0 := Max(k, r/(r-b)*k)
+	return beta, bInf, b0
+}
+
+/*
+bjsPhi is an unexported function that computes the phi function of the
+Bjerksund and Stensland models.
+*/
+func bjsPhi(s float64, t float64, gamma float64, h float64, i float64, v float64, r float64, b float64) float64 {
+	vt := v * Sqrt(t)
+	lambda := (-r + gamma*b + 0.5*gamma*(gamma-1.0)*v*v) * t
+	d := -(Log(s/h) + (b+(gamma-0.5)*v*v)*t) / vt
+	kappa := 2.0*b/(v*v) + (2.0*gamma - 1.0)
+	return Exp(lambda) * Pow(s, gamma) * (CDF(d) - Pow(i/s, kappa)*CDF(d-2.0*Log(i/s)/vt))
+}
+
+/*
+bjsPsi is an unexported function that computes the psi function of the
+Bjerksund and Stensland (2002) model.
+*/
+func bjsPsi(s float64, t2 float64, gamma float64, h float64, i2 float64, i1 float64, t1 float64, v float64, r float64, b float64) float64 {
+	drift := b + (gamma-0.5)*v*v
+	vt1, vt2 := v*Sqrt(t1), v*Sqrt(t2)
+	e1 := (Log(s/i1) + drift*t1) / vt1
+	e2 := (Log(i2*i2/(s*i1)) + drift*t1) / vt1
+	e3 := (Log(s/i1) - drift*t1) / vt1
+	e4 := (Log(i2*i2/(s*i1)) - drift*t1) / vt1
+	f1 := (Log(s/h) + drift*t2) / vt2
+	f2 := (Log(i2*i2/(s*h)) + drift*t2) / vt2
+	f3 := (Log(i1*i1/(s*h)) + drift*t2) / vt2
+	f4 := (Log(s*i1*i1/(h*i2*i2)) + drift*t2) / vt2
+	rho := Sqrt(t1 / t2)
+	lambda := -r + gamma*b + 0.5*gamma*(gamma-1.0)*v*v
+	kappa := 2.0*b/(v*v) + (2.0*gamma - 1.0)
+	return Exp(lambda*t2) * Pow(s, gamma) *
+		(BivariateCDF(-e1, -f1, rho) -
+			Pow(i2/s, kappa)*BivariateCDF(-e2, -f2, rho) -
+			Pow(i1/s, kappa)*BivariateCDF(-e3, -f3, -rho) +
+			Pow(i1/i2, kappa)*BivariateCDF(-e4, -f4, -rho))
+}
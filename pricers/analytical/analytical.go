@@ -31,26 +31,296 @@ value financial instruments and their risks.
 This is a multi-file package and is made up of the following source files:
   analytical.go          provides the common definitions that are used by
                          the other source files in the package;
+  american.go            provides the analytical approximations for American
+                         options;
+  bachelier.go           provides the analytical pricers for the Bachelier
+                         (normal) model, and their implied volatility
+                         solvers;
+  barrier.go             provides the analytical pricers for single-barrier
+                         options;
   blackscholesmerton.go  provides the analytical pricers that belong to the
-                         Black-Scholes-Merton family of pricing models.
+                         Black-Scholes-Merton family of pricing models;
+  calibration.go         provides the calibration of the Heston (1993)
+                         pricing model to implied volatilities;
+  chain.go               provides the batch pricer for option chains;
+  contract.go            provides the pricer entry point for option
+                         contracts;
+  dated.go               provides the date-based entry points to the
+                         analytical pricers;
+  errors.go              provides the structured errors that are returned
+                         by the pricers, and the validation of their inputs;
+  heston.go              provides the Heston (1993) stochastic volatility
+                         pricing model;
+  impliedvol.go          provides the implied volatility solvers for the
+                         Black-Scholes-Merton family of pricing models;
+  payoff.go              provides the closed-form pricer for European
+                         options with the payoffs of the options package;
+  portfolio.go           provides the portfolio pricer that values option
+                         contracts concurrently on a pool of workers;
+  sabr.go                provides the implied volatility expansions of the
+                         SABR model, and their calibration.
 */
 package analytical
 
+import (
+	"fmt"
+	. "math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidConventions is returned when the market conventions for
+scaling the greeks are not positive finite numbers.
+*/
+type ErrInvalidConventions string
+
+func (e ErrInvalidConventions) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
 /*
 ==================
 Common Definitions
 ==================
 */
 
+/*
+Conventions is the structure that holds the market conventions by which the
+pricing methods scale the greeks. The sensitivities to volatility are
+reported per VolShift of volatility, the sensitivities to the passage of
+time per 1/DaysPerYear of a year, and the sensitivities to rates per
+RateShift of rate. The greeks of second and third order in volatility are
+scaled by the square and the cube of VolShift.
+
+Usage (example):
+var out analytical.ModelOutputs
+out.Conventions = &analytical.Conventions{VolShift: analytical.PerUnit, DaysPerYear: analytical.TradingDays, RateShift: analytical.PerBasisPoint}
+err := out.GBSM(ot, s, k, t, v, r, b)
+*/
+type Conventions struct {
+	VolShift    float64
+	DaysPerYear float64
+	RateShift   float64
+}
+
+/*
+Common values of the fields of Conventions.
+*/
+const (
+	PerUnit       = 1.0    // per unit of volatility or rate
+	PerPercent    = 0.01   // per 1% of volatility or rate
+	PerBasisPoint = 0.0001 // per basis point of rate
+	PerYear       = 1.0    // per year
+	CalendarDays  = 365.0  // per calendar day
+	TradingDays   = 252.0  // per trading day
+)
+
+/*
+DefaultConventions returns the market conventions that are used when the
+Conventions field of a ModelOutputs is nil: Vega per 1% of volatility,
+Theta per calendar day, and Rho per 1% of rate.
+*/
+func DefaultConventions() Conventions {
+	return Conventions{VolShift: PerPercent, DaysPerYear: CalendarDays, RateShift: PerPercent}
+}
+
+/*
+Scale returns the given Vega, Theta and Rho, which are per unit of
+volatility, per year and per unit of rate, scaled by the market
+conventions, or by DefaultConventions() if the receiver is nil. The
+pricing methods of the lattice, montecarlo and pde packages scale their
+greeks with it, so that they report them as the pricing methods of this
+package do. It returns the error ErrInvalidConventions if the conventions
+are invalid; otherwise, it returns nil.
+
+Usage (example):
+vega, theta, rho, err := out.Conventions.Scale(vega, theta, rho)
+*/
+func (c *Conventions) Scale(vega float64, theta float64, rho float64) (float64, float64, float64, error) {
+	d := DefaultConventions()
+	if c != nil {
+		d = *c
+	}
+	if err := d.validate(); err != nil {
+		return NaN(), NaN(), NaN(), err
+	}
+	return vega * d.VolShift, theta / d.DaysPerYear, rho * d.RateShift, nil
+}
+
+/*
+validate is an unexported method that returns the error
+ErrInvalidConventions if the market conventions are not positive finite
+numbers; otherwise, it returns nil.
+*/
+func (c Conventions) validate() error {
+	for _, x := range []float64{c.VolShift, c.DaysPerYear, c.RateShift} {
+		if !(x > 0.0) || IsInf(x, 0) {
+			return ErrInvalidConventions("The scaling conventions must be positive finite numbers.")
+		}
+	}
+	return nil
+}
+
 /*
 ModelOutputs is the structure that holds the results returned by the pricing
-methods defined in the package.
+methods defined in the package. The greeks below Rho are only computed by
+the GBSM method and the pricing models built on it; the other pricing
+methods set them to zero.
 */
 type ModelOutputs struct {
-	Value float64
-	Delta float64
-	Gamma float64
-	Vega  float64
-	Theta float64
-	Rho   float64
+	Value     float64
+	Delta     float64
+	Gamma     float64
+	Vega      float64
+	Theta     float64
+	Rho       float64
+	Vanna     float64 // sensitivity of Delta to volatility
+	Volga     float64 // sensitivity of Vega to volatility (also known as Vomma)
+	Charm     float64 // decay of Delta with the passage of time
+	Speed     float64 // sensitivity of Gamma to the spot price
+	Zomma     float64 // sensitivity of Gamma to volatility
+	Color     float64 // decay of Gamma with the passage of time
+	Veta      float64 // decay of Vega with the passage of time
+	Ultima    float64 // sensitivity of Volga to volatility
+	DualDelta float64 // sensitivity of the value to the strike price
+	DualGamma float64 // sensitivity of DualDelta to the strike price
+	CarryRho  float64 // sensitivity of the value to the cost of carry
+	Lambda    float64 // elasticity, i.e. the percentage change in the value per percentage change in the spot price
+
+	Conventions *Conventions // scaling of the greeks; DefaultConventions() if nil
+}
+
+/*
+Raw returns a copy of the ModelOutputs receiver with the scaling of the
+greeks by its Conventions undone, i.e. with the greeks per unit of
+volatility, per year and per unit of rate.
+
+Usage (example):
+raw := out.Raw()
+*/
+func (out *ModelOutputs) Raw() ModelOutputs {
+	raw := *out
+	c := out.conventions()
+	raw.rescale(1.0/c.VolShift, c.DaysPerYear, 1.0/c.RateShift)
+	raw.Conventions = &Conventions{VolShift: PerUnit, DaysPerYear: PerYear, RateShift: PerUnit}
+	return raw
+}
+
+/*
+conventions is an unexported method that returns the market conventions of
+the ModelOutputs receiver.
+*/
+func (out *ModelOutputs) conventions() Conventions {
+	if out.Conventions == nil {
+		return DefaultConventions()
+	}
+	return *out.Conventions
+}
+
+/*
+scale is an unexported method that scales the raw greeks in the fields of
+the ModelOutputs receiver by its market conventions. It returns the error
+ErrInvalidConventions if the conventions are invalid; otherwise, it
+returns nil.
+*/
+func (out *ModelOutputs) scale() error {
+	c := out.conventions()
+	if err := c.validate(); err != nil {
+		return err
+	}
+	out.rescale(c.VolShift, 1.0/c.DaysPerYear, c.RateShift)
+	return nil
+}
+
+/*
+rescale is an unexported method that multiplies the greeks in the fields of
+the ModelOutputs receiver by the given factors for volatility, time and
+rate.
+*/
+func (out *ModelOutputs) rescale(vol float64, time float64, rate float64) {
+	out.Vega *= vol
+	out.Vanna *= vol
+	out.Zomma *= vol
+	out.Volga *= vol * vol
+	out.Ultima *= vol * vol * vol
+	out.Theta *= time
+	out.Charm *= time
+	out.Color *= time
+	out.Veta *= vol * time
+	out.Rho *= rate
+	out.CarryRho *= rate
+}
+
+/*
+check is an unexported method that returns an *OutputError for the first of
+the fields of the ModelOutputs receiver that is not a finite number, given
+the name and the arguments of the pricing method; otherwise, it returns
+nil.
+*/
+func (out *ModelOutputs) check(pricer string, in []field) error {
+	for _, f := range []field{{"Value", out.Value}, {"Delta", out.Delta}, {"Gamma", out.Gamma},
+		{"Vega", out.Vega}, {"Theta", out.Theta}, {"Rho", out.Rho}, {"Vanna", out.Vanna},
+		{"Volga", out.Volga}, {"Charm", out.Charm}, {"Speed", out.Speed}, {"Zomma", out.Zomma},
+		{"Color", out.Color}, {"Veta", out.Veta}, {"Ultima", out.Ultima}, {"DualDelta", out.DualDelta},
+		{"DualGamma", out.DualGamma}, {"CarryRho", out.CarryRho}, {"Lambda", out.Lambda}} {
+		if IsNaN(f.value) || IsInf(f.value, 0) {
+			return &OutputError{pricer, f.name, f.value, inputMap(in)}
+		}
+	}
+	return nil
+}
+
+/*
+Bump sizes used by numericalGreeks for the central differences.
+*/
+const (
+	bumpS = 1.0e-4 // relative to the spot price
+	bumpT = 1.0e-5 // in years
+	bumpV = 1.0e-4 // in units of volatility
+	bumpR = 1.0e-5 // in units of rate
+)
+
+/*
+numericalGreeks is an unexported method that computes the theoretical value
+and greeks of a financial option by revaluing the given pricing function with
+central differences, and saves the computed results in the fields of the
+ModelOutputs receiver scaled by its market conventions, as GBSM does; the
+greeks below Rho are set to zero. Rho is computed by shifting r and b
+together, as GBSM's Rho does. It returns an *OutputError, with the given
+name of the pricing method, if the value or a greek is not a finite
+number, or the error ErrInvalidConventions if the conventions are invalid;
+otherwise, it returns nil.
+*/
+func (out *ModelOutputs) numericalGreeks(pricer string, price func(s, t, v, r, b float64) float64, s float64, t float64, v float64, r float64, b float64) error {
+	ds := bumpS * s
+	value := price(s, t, v, r, b)
+	up, down := price(s+ds, t, v, r, b), price(s-ds, t, v, r, b)
+	out.Value = value
+	out.Delta = (up - down) / (2.0 * ds)
+	out.Gamma = (up - 2.0*value + down) / (ds * ds)
+	// Fall back to one-sided differences when t or v is too small to bump down.
+	if t > bumpT {
+		out.Theta = -(price(s, t+bumpT, v, r, b) - price(s, t-bumpT, v, r, b)) / (2.0 * bumpT)
+	} else {
+		out.Theta = -(price(s, t+bumpT, v, r, b) - value) / bumpT
+	}
+	if v > bumpV {
+		out.Vega = (price(s, t, v+bumpV, r, b) - price(s, t, v-bumpV, r, b)) / (2.0 * bumpV)
+	} else {
+		out.Vega = (price(s, t, v+bumpV, r, b) - value) / bumpV
+	}
+	out.Rho = (price(s, t, v, r+bumpR, b+bumpR) - price(s, t, v, r-bumpR, b-bumpR)) / (2.0 * bumpR)
+	out.Vanna, out.Volga, out.Charm, out.Speed, out.Zomma, out.Color = 0.0, 0.0, 0.0, 0.0, 0.0, 0.0
+	out.Veta, out.Ultima, out.DualDelta, out.DualGamma, out.CarryRho, out.Lambda = 0.0, 0.0, 0.0, 0.0, 0.0, 0.0
+	// Check for pricing error.
+	if err := out.check(pricer, []field{{"s", s}, {"t", t}, {"v", v}, {"r", r}, {"b", b}}); err != nil {
+		return err
+	}
+	// Scaling some of the Greeks based on market conventions.
+	return out.scale()
 }
@@ -0,0 +1,104 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+	"testing"
+)
+
+func TestConventions(t *testing.T) {
+	var def ModelOutputs
+	if err := def.GBSM(Call, 100.0, 95.0, 0.5, 0.25, 0.05, 0.02); err != nil {
+		t.Fatal(err)
+	}
+	out := ModelOutputs{Conventions: &Conventions{VolShift: PerUnit, DaysPerYear: TradingDays, RateShift: PerBasisPoint}}
+	if err := out.GBSM(Call, 100.0, 95.0, 0.5, 0.25, 0.05, 0.02); err != nil {
+		t.Fatal(err)
+	}
+	// The default conventions are per 1% of volatility, per calendar day and per 1% of rate.
+	cases := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"Vega per unit", out.Vega, 100.0 * def.Vega},
+		{"Volga per unit", out.Volga, 10000.0 * def.Volga},
+		{"Theta per trading day", out.Theta, def.Theta * 365.0 / 252.0},
+		{"Charm per trading day", out.Charm, def.Charm * 365.0 / 252.0},
+		{"Veta per unit and trading day", out.Veta, def.Veta * 100.0 * 365.0 / 252.0},
+		{"Rho per basis point", out.Rho, def.Rho / 100.0},
+		{"CarryRho per basis point", out.CarryRho, def.CarryRho / 100.0},
+		{"Delta", out.Delta, def.Delta},
+	}
+	for _, c := range cases {
+		if Abs(c.got-c.want) > 1.0e-12*Max(1.0, Abs(c.want)) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestRaw(t *testing.T) {
+	for _, c := range []*Conventions{nil, {VolShift: PerUnit, DaysPerYear: TradingDays, RateShift: PerBasisPoint}} {
+		out := ModelOutputs{Conventions: c}
+		if err := out.GBSM(Put, 100.0, 105.0, 0.75, 0.3, 0.04, 0.01); err != nil {
+			t.Fatal(err)
+		}
+		raw := out.Raw()
+		// Scaling the raw greeks by the conventions gives back the greeks.
+		vega, theta, rho, err := c.Scale(raw.Vega, raw.Theta, raw.Rho)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Abs(vega-out.Vega) > 1.0e-12 || Abs(theta-out.Theta) > 1.0e-12 || Abs(rho-out.Rho) > 1.0e-12 {
+			t.Errorf("Scale(Raw()) = (%v, %v, %v), want (%v, %v, %v)", vega, theta, rho, out.Vega, out.Theta, out.Rho)
+		}
+		// The raw greeks are those of the per unit and per year conventions.
+		want := ModelOutputs{Conventions: &Conventions{VolShift: PerUnit, DaysPerYear: PerYear, RateShift: PerUnit}}
+		if err := want.GBSM(Put, 100.0, 105.0, 0.75, 0.3, 0.04, 0.01); err != nil {
+			t.Fatal(err)
+		}
+		for _, x := range [][2]float64{{raw.Vega, want.Vega}, {raw.Theta, want.Theta}, {raw.Rho, want.Rho},
+			{raw.Volga, want.Volga}, {raw.Ultima, want.Ultima}, {raw.Veta, want.Veta}, {raw.Charm, want.Charm}} {
+			if Abs(x[0]-x[1]) > 1.0e-9*Max(1.0, Abs(x[1])) {
+				t.Errorf("Raw() = %v, want %v", x[0], x[1])
+			}
+		}
+	}
+}
+
+func TestInvalidConventions(t *testing.T) {
+	for _, c := range []Conventions{{}, {VolShift: PerPercent, DaysPerYear: Inf(1), RateShift: PerPercent}, {VolShift: -0.01, DaysPerYear: CalendarDays, RateShift: PerPercent}} {
+		out := ModelOutputs{Conventions: &c}
+		if err := out.GBSM(Call, 100.0, 100.0, 1.0, 0.2, 0.05, 0.05); err == nil {
+			t.Errorf("GBSM with the conventions %v returned no error", c)
+		} else if _, ok := err.(ErrInvalidConventions); !ok {
+			t.Errorf("GBSM with the conventions %v returned %v, want ErrInvalidConventions", c, err)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "github.com/kervinlow/quantstruct/math"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+)
+
+/*
+================================================================
+Provides the closed-form Bachelier (normal) pricing models, and
+their implied volatility solvers.
+================================================================
+*/
+
+/*
+--------------------------------------------------------------------------
+GB1900 -- Generalized Bachelier (1900) pricing model
+
+Description:
+A method that computes the theoretical value and greeks of a financial
+option whose underlying forward price follows an arithmetic Brownian
+motion with the normal (absolute) volatility v, and saves the computed
+results in the fields of the ModelOutputs receiver, with the greeks scaled
+by its market conventions. The forward price is s*Exp(b*t), and the spot,
+forward and strike prices may be zero or negative. Vega and the other
+sensitivities to volatility are per VolShift of normal volatility, in
+units of the underlying price (e.g. set VolShift to PerBasisPoint for a
+rate quoted in decimals). At expiry (t = 0) or at zero volatility (v = 0),
+the option is valued at the discounted intrinsic value of the forward, as
+GBSM does. It returns an *InputError if the option type is neither Call
+nor Put, if t or v is negative, or if any argument is not a finite
+number; an *OutputError if the value or a greek is not a finite number; or
+the error ErrInvalidConventions if the conventions are invalid;
+otherwise, it returns nil.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.GB1900(ot, s, k, t, v, r, b)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+v  normal volatility of the underlying forward price
+r  risk-free rate
+b  cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) GB1900(ot OptionType, s float64, k float64, t float64, v float64, r float64, b float64) error {
+	return out.gb1900("GB1900", ot, s, k, t, v, r, b)
+}
+
+/*
+gb1900 is an unexported method that computes the results of the GB1900
+method for the named pricer, so that B1900 reports its own name in the
+errors that it returns.
+*/
+func (out *ModelOutputs) gb1900(pricer string, ot OptionType, s float64, k float64, t float64, v float64, r float64, b float64) error {
+	// Validate the inputs before computing anything.
+	in := []field{{"ot", float64(ot)}, {"s", s}, {"k", k}, {"t", t}, {"v", v}, {"r", r}, {"b", b}}
+	if err := checkOptionType(pricer, ot, in); err != nil {
+		return err
+	}
+	if err := checkInputs(pricer, in); err != nil {
+		return err
+	}
+	if err := checkNonNegative(pricer, in, "t", "v"); err != nil {
+		return err
+	}
+	f, disc, carry := s*Exp(b*t), Exp((-r)*t), Exp(b*t)
+	sd := v * Sqrt(t)
+	*out = ModelOutputs{Conventions: out.Conventions}
+	// At expiry or at zero volatility, d is undefined; take its limit instead.
+	if sd == 0.0 {
+		n := 0.5
+		switch {
+		case f > k:
+			n = 1.0
+		case f < k:
+			n = 0.0
+		}
+		if ot == Put {
+			n = n - 1.0
+		}
+		out.Value = disc * (f - k) * n
+		out.Delta = disc * carry * n
+		out.Theta = (r * out.Value) - (disc * b * f * n)
+		out.Rho = t * ((disc * f * n) - out.Value)
+		out.Charm = (-(b - r)) * out.Delta
+		out.DualDelta = (-disc) * n
+		out.CarryRho = t * disc * f * n
+		if f == k {
+			out.Vega = disc * Sqrt(t) * PDF(0.0)
+		}
+		return out.finish(pricer, in, s)
+	}
+	d := (f - k) / sd
+	pdf := PDF(d)
+	// N is the probability of exercise, signed by the option type.
+	var n float64
+	switch ot {
+	case Call:
+		n = CDF(d)
+	case Put:
+		n = -CDF(-d)
+	}
+	dd := (b * f / sd) - (d / (2.0 * t)) // derivative of d with respect to t
+	out.Value = disc * (((f - k) * n) + (sd * pdf))
+	out.Delta = disc * carry * n
+	out.Gamma = disc * carry * carry * pdf / sd
+	out.Vega = disc * Sqrt(t) * pdf
+	out.Theta = (r * out.Value) - (disc * ((b * f * n) + (v * pdf / (2.0 * Sqrt(t)))))
+	out.Rho = t * ((disc * f * n) - out.Value)
+	out.Vanna = (-disc) * carry * pdf * d / v
+	out.Volga = out.Vega * d * d / v
+	out.Charm = -(((b - r) * out.Delta) + (disc * carry * pdf * dd))
+	out.Speed = (-out.Gamma) * d * carry / sd
+	out.Zomma = out.Gamma * ((d * d) - 1.0) / v
+	out.Color = -out.Gamma * ((2.0 * b) - r - (d * dd) - (1.0 / (2.0 * t)))
+	out.Veta = -out.Vega * ((-r) + (1.0 / (2.0 * t)) - (d * dd))
+	out.Ultima = out.Volga * ((d * d) - 3.0) / v
+	out.DualDelta = (-disc) * n
+	out.DualGamma = disc * pdf / sd
+	out.CarryRho = t * disc * f * n
+	return out.finish(pricer, in, s)
+}
+
+/*
+--------------------------------------------------------------------------
+B1900 -- Bachelier (1900) pricing model
+
+Description:
+A method that computes the theoretical value and greeks of an option on a
+forward or futures contract whose price follows an arithmetic Brownian
+motion with the normal (absolute) volatility v, and saves the computed
+results in the fields of the ModelOutputs receiver. The forward and
+strike prices may be zero or negative. It returns the same errors as
+GB1900.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.B1900(ot, f, k, t, v, r)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+f  forward price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+v  normal volatility of the forward price
+r  risk-free rate
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) B1900(ot OptionType, f float64, k float64, t float64, v float64, r float64) error {
+	return out.gb1900("B1900", ot, f, k, t, v, r, 0.0)
+}
+
+/*
+--------------------------------------------------------------------------
+GB1900ImpliedVol -- Normal implied volatility of the Generalized Bachelier
+(1900) pricing model
+
+Description:
+A function that returns the normal volatility at which the GB1900 method
+reprices a financial option to the target price p. It returns the error
+ErrBelowIntrinsic if p is below the option's value at zero volatility,
+and the error ErrNoConvergence if the solver fails to converge;
+otherwise, it returns nil.
+
+Usage:
+vol, err := analytical.GB1900ImpliedVol(ot, p, s, k, t, r, b)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+p  target price of the option
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+r  risk-free rate
+b  cost of carry
+--------------------------------------------------------------------------
+*/
+func GB1900ImpliedVol(ot OptionType, p float64, s float64, k float64, t float64, r float64, b float64) (float64, error) {
+	// The option's value at zero volatility bounds the target price; there is no upper bound.
+	f, disc := s*Exp(b*t), Exp((-r)*t)
+	var lower float64
+	switch ot {
+	case Call:
+		lower = disc * Max(f-k, 0.0)
+	case Put:
+		lower = disc * Max(k-f, 0.0)
+	}
+	price := func(v float64) (float64, float64, error) {
+		var out ModelOutputs
+		if err := out.GB1900(ot, s, k, t, v, r, b); err != nil {
+			return 0.0, 0.0, err
+		}
+		return out.Value, out.Raw().Vega, nil
+	}
+	// Start the iteration from the at-the-money approximation of the time value.
+	guess := (p - lower) / (disc * Sqrt(t) * PDF(0.0))
+	return impliedVol(p, lower, Inf(1), guess, price)
+}
+
+/*
+--------------------------------------------------------------------------
+B1900ImpliedVol -- Normal implied volatility of the Bachelier (1900)
+pricing model
+
+Description:
+A function that returns the normal volatility at which the B1900 method
+reprices an option on a forward or futures contract to the target price
+p. It returns the same errors as GB1900ImpliedVol.
+
+Usage:
+vol, err := analytical.B1900ImpliedVol(ot, p, f, k, t, r)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+p  target price of the option
+f  forward price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+r  risk-free rate
+--------------------------------------------------------------------------
+*/
+func B1900ImpliedVol(ot OptionType, p float64, f float64, k float64, t float64, r float64) (float64, error) {
+	return GB1900ImpliedVol(ot, p, f, k, t, r, 0.0)
+}
+
+/*
+--------------------------------------------------------------------------
+NormalToLognormalVol -- Conversion of a normal volatility to a lognormal
+volatility
+
+Description:
+A function that returns the lognormal (Black) volatility at which the
+B1976 method gives the same value as the B1900 method with the normal
+volatility vn, for an option with the forward price f, strike price k
+and time to expiry t. The conversion is exact, and uses the out-of-the-
+money option. The forward and strike prices must be positive. It returns
+the same errors as B1976ImpliedVol.
+
+Usage:
+vol, err := analytical.NormalToLognormalVol(f, k, t, vn)
+
+Arguments:
+f  forward price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+vn normal volatility of the forward price
+--------------------------------------------------------------------------
+*/
+func NormalToLognormalVol(f float64, k float64, t float64, vn float64) (float64, error) {
+	ot := otmType(f, k)
+	var out ModelOutputs
+	if err := out.B1900(ot, f, k, t, vn, 0.0); err != nil {
+		return NaN(), err
+	}
+	return B1976ImpliedVol(ot, out.Value, f, k, t, 0.0)
+}
+
+/*
+--------------------------------------------------------------------------
+LognormalToNormalVol -- Conversion of a lognormal volatility to a normal
+volatility
+
+Description:
+A function that returns the normal volatility at which the B1900 method
+gives the same value as the B1976 method with the lognormal (Black)
+volatility vl, for an option with the forward price f, strike price k
+and time to expiry t. The conversion is exact, and uses the out-of-the-
+money option. The forward and strike prices must be positive. It returns
+the same errors as B1900ImpliedVol.
+
+Usage:
+vol, err := analytical.LognormalToNormalVol(f, k, t, vl)
+
+Arguments:
+f  forward price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+vl lognormal volatility of the forward price
+--------------------------------------------------------------------------
+*/
+func LognormalToNormalVol(f float64, k float64, t float64, vl float64) (float64, error) {
+	ot := otmType(f, k)
+	var out ModelOutputs
+	if err := out.B1976(ot, f, k, t, vl, 0.0); err != nil {
+		return NaN(), err
+	}
+	return B1900ImpliedVol(ot, out.Value, f, k, t, 0.0)
+}
+
+/*
+otmType is an unexported function that returns the type of the
+out-of-the-money option for the forward price f and strike price k, i.e.
+a call if k is at or above f, and a put otherwise.
+*/
+func otmType(f float64, k float64) OptionType {
+	if k >= f {
+		return Call
+	}
+	return Put
+}
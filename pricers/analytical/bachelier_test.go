@@ -0,0 +1,185 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"errors"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+	"testing"
+)
+
+/*
+bachelierCases are the spot, strike and normal volatility of the options
+on which the Bachelier pricers are checked, including negative prices.
+*/
+var bachelierCases = []struct{ s, k, v float64 }{
+	{100.0, 90.0, 20.0}, {100.0, 100.0, 20.0}, {100.0, 110.0, 20.0},
+	{0.01, 0.015, 0.008}, {-0.002, 0.001, 0.006}, {-0.005, -0.003, 0.004},
+}
+
+func TestGB1900ATM(t *testing.T) {
+	// At the money, the value is the discounted expected absolute move of
+	// the forward price over two.
+	var out ModelOutputs
+	if err := out.B1900(Call, 0.02, 0.02, 2.0, 0.01, 0.03); err != nil {
+		t.Fatal(err)
+	}
+	want := Exp(-0.03*2.0) * 0.01 * Sqrt(2.0) / Sqrt(2.0*Pi)
+	if Abs(out.Value-want) > 1.0e-15 {
+		t.Errorf("B1900 at the money = %v, want %v", out.Value, want)
+	}
+}
+
+func TestGB1900PutCallParity(t *testing.T) {
+	const T, r, b = 1.5, 0.03, 0.01
+	for _, c := range bachelierCases {
+		var call, put ModelOutputs
+		if err := call.GB1900(Call, c.s, c.k, T, c.v, r, b); err != nil {
+			t.Fatal(err)
+		}
+		if err := put.GB1900(Put, c.s, c.k, T, c.v, r, b); err != nil {
+			t.Fatal(err)
+		}
+		want := Exp(-r*T) * (c.s*Exp(b*T) - c.k)
+		if got := call.Value - put.Value; Abs(got-want) > 1.0e-12*Max(Abs(c.s), 1.0) {
+			t.Errorf("GB1900(s=%v, k=%v) call - put = %v, want %v", c.s, c.k, got, want)
+		}
+		if got := call.Delta - put.Delta; Abs(got-Exp((b-r)*T)) > 1.0e-12 {
+			t.Errorf("GB1900(s=%v, k=%v) call delta - put delta = %v, want %v", c.s, c.k, got, Exp((b-r)*T))
+		}
+		if call.Vega != put.Vega || call.Gamma != put.Gamma {
+			t.Errorf("GB1900(s=%v, k=%v) call and put vega or gamma differ", c.s, c.k)
+		}
+	}
+}
+
+func TestGB1900GreeksFiniteDifference(t *testing.T) {
+	const s, k, tte, v, r, b = 100.0, 95.0, 0.75, 20.0, 0.06, 0.02
+	for name, ot := range map[string]OptionType{"Call": Call, "Put": Put} {
+		// price returns the unscaled results of GB1900 with the inputs bumped by
+		// the given amounts; the rate bump moves the cost of carry with it, as
+		// Rho assumes.
+		price := func(ds, dk, dt, dv, dr, db float64) ModelOutputs {
+			var out ModelOutputs
+			if err := out.GB1900(ot, s+ds, k+dk, tte+dt, v+dv, r+dr, b+dr+db); err != nil {
+				t.Fatal(err)
+			}
+			return out.Raw()
+		}
+		out := price(0, 0, 0, 0, 0, 0)
+		// Each greek is compared with the central difference of the value or of a
+		// lower-order greek.
+		const hs, hk, ht, hv, hr = 1.0e-3, 1.0e-3, 1.0e-5, 1.0e-4, 1.0e-5
+		cases := []struct {
+			name string
+			got  float64
+			want float64
+		}{
+			{"Delta", out.Delta, (price(hs, 0, 0, 0, 0, 0).Value - price(-hs, 0, 0, 0, 0, 0).Value) / (2.0 * hs)},
+			{"Gamma", out.Gamma, (price(hs, 0, 0, 0, 0, 0).Delta - price(-hs, 0, 0, 0, 0, 0).Delta) / (2.0 * hs)},
+			{"Vega", out.Vega, (price(0, 0, 0, hv, 0, 0).Value - price(0, 0, 0, -hv, 0, 0).Value) / (2.0 * hv)},
+			{"Theta", out.Theta, -(price(0, 0, ht, 0, 0, 0).Value - price(0, 0, -ht, 0, 0, 0).Value) / (2.0 * ht)},
+			{"Rho", out.Rho, (price(0, 0, 0, 0, hr, 0).Value - price(0, 0, 0, 0, -hr, 0).Value) / (2.0 * hr)},
+			{"Vanna", out.Vanna, (price(0, 0, 0, hv, 0, 0).Delta - price(0, 0, 0, -hv, 0, 0).Delta) / (2.0 * hv)},
+			{"Volga", out.Volga, (price(0, 0, 0, hv, 0, 0).Vega - price(0, 0, 0, -hv, 0, 0).Vega) / (2.0 * hv)},
+			{"Charm", out.Charm, -(price(0, 0, ht, 0, 0, 0).Delta - price(0, 0, -ht, 0, 0, 0).Delta) / (2.0 * ht)},
+			{"Speed", out.Speed, (price(hs, 0, 0, 0, 0, 0).Gamma - price(-hs, 0, 0, 0, 0, 0).Gamma) / (2.0 * hs)},
+			{"Zomma", out.Zomma, (price(0, 0, 0, hv, 0, 0).Gamma - price(0, 0, 0, -hv, 0, 0).Gamma) / (2.0 * hv)},
+			{"Color", out.Color, -(price(0, 0, ht, 0, 0, 0).Gamma - price(0, 0, -ht, 0, 0, 0).Gamma) / (2.0 * ht)},
+			{"Veta", out.Veta, -(price(0, 0, ht, 0, 0, 0).Vega - price(0, 0, -ht, 0, 0, 0).Vega) / (2.0 * ht)},
+			{"Ultima", out.Ultima, (price(0, 0, 0, hv, 0, 0).Volga - price(0, 0, 0, -hv, 0, 0).Volga) / (2.0 * hv)},
+			{"DualDelta", out.DualDelta, (price(0, hk, 0, 0, 0, 0).Value - price(0, -hk, 0, 0, 0, 0).Value) / (2.0 * hk)},
+			{"DualGamma", out.DualGamma, (price(0, hk, 0, 0, 0, 0).Value - 2.0*out.Value + price(0, -hk, 0, 0, 0, 0).Value) / (hk * hk)},
+			{"CarryRho", out.CarryRho, (price(0, 0, 0, 0, 0, hr).Value - price(0, 0, 0, 0, 0, -hr).Value) / (2.0 * hr)},
+		}
+		for _, c := range cases {
+			if Abs(c.got-c.want) > 1.0e-5*Max(1.0, Abs(c.want)) {
+				t.Errorf("GB1900(%s) %s = %v, want %v", name, c.name, c.got, c.want)
+			}
+		}
+	}
+}
+
+func TestB1900InvalidInputs(t *testing.T) {
+	var out ModelOutputs
+	var ie *InputError
+	if err := out.B1900(Call, 0.02, 0.02, -1.0, 0.01, 0.03); !errors.As(err, &ie) || ie.Pricer != "B1900" || ie.Field != "t" {
+		t.Errorf("B1900 with a negative time to expiry returned %v, want an *InputError of B1900 for t", err)
+	}
+}
+
+func TestGB1900ImpliedVolRoundTrip(t *testing.T) {
+	const T, r, b = 1.5, 0.03, 0.01
+	for _, ot := range []OptionType{Call, Put} {
+		for _, c := range bachelierCases {
+			var out ModelOutputs
+			if err := out.GB1900(ot, c.s, c.k, T, c.v, r, b); err != nil {
+				t.Fatal(err)
+			}
+			vol, err := GB1900ImpliedVol(ot, out.Value, c.s, c.k, T, r, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if Abs(vol-c.v) > 1.0e-8*c.v {
+				t.Errorf("GB1900ImpliedVol(%v, s=%v, k=%v) = %v, want %v", ot, c.s, c.k, vol, c.v)
+			}
+		}
+	}
+}
+
+func TestB1900ImpliedVolRoundTrip(t *testing.T) {
+	for _, ot := range []OptionType{Call, Put} {
+		var out ModelOutputs
+		if err := out.B1900(ot, -0.001, 0.002, 5.0, 0.0075, 0.02); err != nil {
+			t.Fatal(err)
+		}
+		vol, err := B1900ImpliedVol(ot, out.Value, -0.001, 0.002, 5.0, 0.02)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Abs(vol-0.0075) > 1.0e-10 {
+			t.Errorf("B1900ImpliedVol(%v) = %v, want 0.0075", ot, vol)
+		}
+	}
+}
+
+func TestNormalLognormalVolRoundTrip(t *testing.T) {
+	for _, k := range []float64{0.02, 0.03, 0.045} {
+		vl, err := NormalToLognormalVol(0.03, k, 2.0, 0.006)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vn, err := LognormalToNormalVol(0.03, k, 2.0, vl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Abs(vn-0.006) > 1.0e-10 {
+			t.Errorf("LognormalToNormalVol(NormalToLognormalVol(0.006)) at k=%v = %v", k, vn)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "github.com/kervinlow/quantstruct/math"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+)
+
+/*
+========================================================================
+Provides the closed-form pricing models for valuing single-barrier
+options.
+========================================================================
+*/
+
+/*
+--------------------------------------------------------------------------
+RR1991 -- Reiner and Rubinstein (1991) pricing model
+
+Description:
+A method that computes the theoretical value and greeks of a standard
+single-barrier option (down-and-in, up-and-in, down-and-out or up-and-out
+call or put) with a cash rebate, and saves the computed results in the
+fields of the ModelOutputs receiver. The rebate of a knock-in option is
+paid at expiry if the barrier was never touched; the rebate of a
+knock-out option is paid as soon as the barrier is touched. The greeks
+are computed numerically, and are scaled by the same market conventions
+as GBSM. It returns an *InputError if the option type is neither Call nor
+Put, if the barrier type is not one of the BarrierType values, if s, k, h,
+t or v is not positive, or if any argument is not a finite number, or an
+*OutputError if the value or a greek is not a finite number; otherwise, it
+returns nil.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.RR1991(ot, bt, s, k, h, x, t, v, r, b)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+bt barrier type (options.DownAndIn, options.UpAndIn,
+   options.DownAndOut or options.UpAndOut from the
+   options package)
+s  spot price of the underlying instrument
+k  strike price of the option
+h  barrier level
+x  cash rebate
+t  time to expiry of the option
+v  volatility of the underlying instrument
+r  risk-free rate
+b  cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) RR1991(ot OptionType, bt BarrierType, s float64, k float64, h float64, x float64, t float64, v float64, r float64, b float64) error {
+	in := []field{{"ot", float64(ot)}, {"bt", float64(bt)}, {"s", s}, {"k", k}, {"h", h}, {"x", x}, {"t", t}, {"v", v}, {"r", r}, {"b", b}}
+	if err := checkOptionType("RR1991", ot, in); err != nil {
+		return err
+	}
+	if err := checkBarrierType("RR1991", bt, in); err != nil {
+		return err
+	}
+	if err := checkInputs("RR1991", in, "s", "k", "h", "t", "v"); err != nil {
+		return err
+	}
+	price := func(s, t, v, r, b float64) float64 {
+		return getRR1991Value(ot, bt, s, k, h, x, t, v, r, b)
+	}
+	return out.numericalGreeks("RR1991", price, s, t, v, r, b)
+}
+
+/*
+getRR1991Value is an unexported function that computes the theoretical
+value of a single-barrier option using the Reiner and Rubinstein (1991)
+pricing model.
+*/
+func getRR1991Value(ot OptionType, bt BarrierType, s float64, k float64, h float64, x float64, t float64, v float64, r float64, b float64) float64 {
+	down := bt == DownAndIn || bt == DownAndOut
+	in := bt == DownAndIn || bt == UpAndIn
+	// Once the barrier has been touched, a knock-in option becomes a vanilla
+	// option and a knock-out option is worth its rebate.
+	if (down && s <= h) || (!down && s >= h) {
+		if in {
+			var vanilla ModelOutputs
+			vanilla.GBSM(ot, s, k, t, v, r, b)
+			return vanilla.Value
+		}
+		return x
+	}
+	// eta is 1 for a down barrier and -1 for an up barrier; phi is 1 for a
+	// call and -1 for a put.
+	eta, phi := 1.0, 1.0
+	if !down {
+		eta = -1.0
+	}
+	if ot == Put {
+		phi = -1.0
+	}
+	vt := v * Sqrt(t)
+	mu := (b - v*v/2.0) / (v * v)
+	lambda := Sqrt(mu*mu + 2.0*r/(v*v))
+	x1 := Log(s/k)/vt + (1.0+mu)*vt
+	x2 := Log(s/h)/vt + (1.0+mu)*vt
+	y1 := Log(h*h/(s*k))/vt + (1.0+mu)*vt
+	y2 := Log(h/s)/vt + (1.0+mu)*vt
+	z := Log(h/s)/vt + lambda*vt
+	carry, disc := s*Exp((b-r)*t), Exp((-r)*t)
+	hs := h / s
+	A := phi*carry*CDF(phi*x1) - phi*k*disc*CDF(phi*x1-phi*vt)
+	B := phi*carry*CDF(phi*x2) - phi*k*disc*CDF(phi*x2-phi*vt)
+	C := phi*carry*Pow(hs, 2.0*(mu+1.0))*CDF(eta*y1) - phi*k*disc*Pow(hs, 2.0*mu)*CDF(eta*y1-eta*vt)
+	D := phi*carry*Pow(hs, 2.0*(mu+1.0))*CDF(eta*y2) - phi*k*disc*Pow(hs, 2.0*mu)*CDF(eta*y2-eta*vt)
+	E := x * disc * (CDF(eta*x2-eta*vt) - Pow(hs, 2.0*mu)*CDF(eta*y2-eta*vt))
+	F := x * (Pow(hs, mu+lambda)*CDF(eta*z) + Pow(hs, mu-lambda)*CDF(eta*z-2.0*eta*lambda*vt))
+	above := k > h
+	switch {
+	case ot == Call && bt == DownAndIn:
+		if above {
+			return C + E
+		}
+		return A - B + D + E
+	case ot == Call && bt == UpAndIn:
+		if above {
+			return A + E
+		}
+		return B - C + D + E
+	case ot == Put && bt == DownAndIn:
+		if above {
+			return B - C + D + E
+		}
+		return A + E
+	case ot == Put && bt == UpAndIn:
+		if above {
+			return A - B + D + E
+		}
+		return C + E
+	case ot == Call && bt == DownAndOut:
+		if above {
+			return A - C + F
+		}
+		return B - D + F
+	case ot == Call && bt == UpAndOut:
+		if above {
+			return F
+		}
+		return A - B + C - D + F
+	case ot == Put && bt == DownAndOut:
+		if above {
+			return A - B + C - D + F
+		}
+		return F
+	default: // ot == Put && bt == UpAndOut, as checked by RR1991
+		if above {
+			return B - D + F
+		}
+		return A - C + F
+	}
+}
@@ -0,0 +1,113 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"errors"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+	"testing"
+)
+
+/*
+The values of the standard barrier options in Table 4-13 of Haug (2007),
+The Complete Guide to Option Pricing Formulas, 2nd edition, for s = 100,
+x = 3, t = 0.5, r = 0.08 and b = 0.04, at the volatilities 0.25 and 0.30.
+*/
+var haugBarrierTable = []struct {
+	ot     OptionType
+	bt     BarrierType
+	k      float64
+	h      float64
+	values [2]float64
+}{
+	{Call, DownAndOut, 90.0, 95.0, [2]float64{9.0246, 8.8334}},
+	{Call, DownAndOut, 100.0, 95.0, [2]float64{6.7924, 7.0285}},
+	{Call, DownAndOut, 110.0, 95.0, [2]float64{4.8759, 5.4137}},
+	{Call, DownAndOut, 90.0, 100.0, [2]float64{3.0000, 3.0000}},
+	{Call, DownAndOut, 100.0, 100.0, [2]float64{3.0000, 3.0000}},
+	{Call, DownAndOut, 110.0, 100.0, [2]float64{3.0000, 3.0000}},
+	{Call, UpAndOut, 90.0, 105.0, [2]float64{2.6789, 2.6341}},
+	{Call, UpAndOut, 100.0, 105.0, [2]float64{2.3580, 2.4389}},
+	{Call, UpAndOut, 110.0, 105.0, [2]float64{2.3453, 2.4315}},
+	{Call, DownAndIn, 90.0, 95.0, [2]float64{7.7627, 9.0093}},
+	{Call, DownAndIn, 100.0, 95.0, [2]float64{4.0109, 5.1370}},
+	{Call, DownAndIn, 110.0, 95.0, [2]float64{2.0576, 2.8517}},
+	{Call, DownAndIn, 90.0, 100.0, [2]float64{13.8333, 14.8816}},
+	{Call, DownAndIn, 100.0, 100.0, [2]float64{7.8494, 9.2045}},
+	{Call, DownAndIn, 110.0, 100.0, [2]float64{3.9795, 5.3043}},
+	{Call, UpAndIn, 90.0, 105.0, [2]float64{14.1112, 15.2098}},
+	{Call, UpAndIn, 100.0, 105.0, [2]float64{8.4482, 9.7278}},
+	{Call, UpAndIn, 110.0, 105.0, [2]float64{4.5910, 5.8350}},
+	{Put, DownAndOut, 90.0, 95.0, [2]float64{2.2798, 2.4170}},
+	{Put, DownAndOut, 100.0, 95.0, [2]float64{2.2947, 2.4258}},
+	{Put, DownAndOut, 110.0, 95.0, [2]float64{2.6252, 2.6246}},
+	{Put, DownAndOut, 90.0, 100.0, [2]float64{3.0000, 3.0000}},
+	{Put, DownAndOut, 100.0, 100.0, [2]float64{3.0000, 3.0000}},
+	{Put, DownAndOut, 110.0, 100.0, [2]float64{3.0000, 3.0000}},
+	{Put, UpAndOut, 90.0, 105.0, [2]float64{3.7760, 4.2293}},
+	{Put, UpAndOut, 100.0, 105.0, [2]float64{5.4932, 5.8032}},
+	{Put, UpAndOut, 110.0, 105.0, [2]float64{7.5187, 7.5649}},
+	{Put, DownAndIn, 90.0, 95.0, [2]float64{2.9586, 3.8769}},
+	{Put, DownAndIn, 100.0, 95.0, [2]float64{6.5677, 7.7989}},
+	{Put, DownAndIn, 110.0, 95.0, [2]float64{11.9752, 13.3078}},
+	{Put, DownAndIn, 90.0, 100.0, [2]float64{2.2845, 3.3328}},
+	{Put, DownAndIn, 100.0, 100.0, [2]float64{5.9085, 7.2636}},
+	{Put, DownAndIn, 110.0, 100.0, [2]float64{11.6465, 12.9713}},
+	{Put, UpAndIn, 90.0, 105.0, [2]float64{1.4653, 2.0658}},
+	{Put, UpAndIn, 100.0, 105.0, [2]float64{3.3721, 4.4226}},
+	{Put, UpAndIn, 110.0, 105.0, [2]float64{7.0846, 8.3686}},
+}
+
+func TestRR1991HaugTable(t *testing.T) {
+	for _, c := range haugBarrierTable {
+		for i, v := range []float64{0.25, 0.30} {
+			var out ModelOutputs
+			if err := out.RR1991(c.ot, c.bt, 100.0, c.k, c.h, 3.0, 0.5, v, 0.08, 0.04); err != nil {
+				t.Fatalf("RR1991(%v, %v, k=%v, h=%v, v=%v): %v", c.ot, c.bt, c.k, c.h, v, err)
+			}
+			// The table is rounded to 4 decimal places, and some of its last
+			// digits are off by one.
+			if Abs(out.Value-c.values[i]) > 1.5e-4 {
+				t.Errorf("RR1991(%v, %v, k=%v, h=%v, v=%v) = %.4f, want %.4f", c.ot, c.bt, c.k, c.h, v, out.Value, c.values[i])
+			}
+		}
+	}
+}
+
+func TestRR1991InvalidTypes(t *testing.T) {
+	var out ModelOutputs
+	var ie *InputError
+	err := out.RR1991(Call, BarrierType(4), 100.0, 100.0, 95.0, 3.0, 0.5, 0.25, 0.08, 0.04)
+	if !errors.As(err, &ie) || ie.Field != "bt" {
+		t.Errorf("RR1991 with an invalid barrier type returned %v, want an *InputError for bt", err)
+	}
+	err = out.RR1991(OptionType(2), DownAndOut, 100.0, 100.0, 95.0, 3.0, 0.5, 0.25, 0.08, 0.04)
+	if !errors.As(err, &ie) || ie.Field != "ot" {
+		t.Errorf("RR1991 with an invalid option type returned %v, want an *InputError for ot", err)
+	}
+}
@@ -28,27 +28,13 @@ package analytical
 
 import (
 	"fmt"
+	"github.com/kervinlow/quantstruct/curves"
 	. "github.com/kervinlow/quantstruct/equity"
 	. "github.com/kervinlow/quantstruct/math"
 	. "github.com/kervinlow/quantstruct/options"
 	. "math"
 )
 
-/*
-===============
-Types of Errors
-===============
-*/
-
-/*
-The error ErrPricing is returned when a pricing error has occurred.
-*/
-type ErrPricing string
-
-func (e ErrPricing) Error() string {
-	return fmt.Sprintf("%s", string(e))
-}
-
 /*
 ==========================================================================
 Provides the closed-form Black-Scholes-Merton family of pricing models for
@@ -62,9 +48,19 @@ GBSM -- Generalized Black Scholes Merton pricing model
 
 Description:
 A method that computes the theoretical value and greeks of a financial
-option, and saves the computed results in the fields of the ModelOutputs
-receiver. It returns the error ErrPricing if a pricing error has occurred;
-otherwise, it returns nil.
+option, including the higher-order and cross greeks, and saves the computed
+results in the fields of the ModelOutputs receiver, with the greeks scaled
+by its market conventions. At expiry (t = 0) or at zero volatility
+(v = 0), the option is valued at the discounted intrinsic value of the
+forward, with the limiting greeks: Delta is the discounted 0, 1/2 or 1
+(-1, -1/2 or 0 for a put) as the forward is below, at or above the
+strike price, and Gamma and Vega are zero, except for Vega at the money
+at zero volatility. The inputs are validated before the option is priced.
+It returns an *InputError if the option type is neither Call nor Put, if
+s or k is not positive, if t or v is negative, or if any argument is not
+a finite number; an *OutputError if the value or a greek is not a finite
+number; or the error ErrInvalidConventions if the conventions are
+invalid; otherwise, it returns nil.
 
 Usage:
 var out analytical.ModelOutputs
@@ -82,142 +78,109 @@ b  cost of carry
 --------------------------------------------------------------------------
 */
 func (out *ModelOutputs) GBSM(ot OptionType, s float64, k float64, t float64, v float64, r float64, b float64) error {
-	// Compute d1 and d2 as specified by the pricing model.
-	d1 := (Log(s/k) + ((b + v*v/2.0) * t)) / (v * Sqrt(t))
-	d2 := d1 - (v * Sqrt(t))
-	var ch [6]chan float64 // allocate 6 channels for the channel array ch
-	// Create each channel, assign it to the channel array ch, and pass each channel to a Goroutine.
-	for n := 0; n < len(ch); n++ {
-		ch[n] = make(chan float64)
-		switch n {
-		case 0:
-			go getGBSMValue(ch[n], ot, d1, d2, s, k, t, r, b)
-		case 1:
-			go getGBSMDelta(ch[n], ot, d1, t, r, b)
-		case 2:
-			go getGBSMTheta(ch[n], ot, d1, d2, s, k, t, v, r, b)
-		case 3:
-			go getGBSMRho(ch[n], ot, d2, k, t, r)
-		case 4:
-			go getGBSMGamma(ch[n], ot, d1, s, t, v, r, b)
-		case 5:
-			go getGBSMVega(ch[n], ot, d1, s, t, r, b)
-		}
-	}
-	// Receive the computed result from each channel, and store it in the ModelOutputs receiver.
-	for i := range ch {
-		for result := range ch[i] {
-			switch i {
-			case 0:
-				out.Value = result
-			case 1:
-				out.Delta = result
-			case 2:
-				out.Theta = result
-			case 3:
-				out.Rho = result
-			case 4:
-				out.Gamma = result
-			case 5:
-				out.Vega = result
-			}
-		}
-	}
-	// Check for pricing error.
-	if IsNaN(out.Value) || IsInf(out.Value, 0) || IsNaN(out.Delta) || IsInf(out.Delta, 0) ||
-		IsNaN(out.Gamma) || IsInf(out.Gamma, 0) || IsNaN(out.Vega) || IsInf(out.Vega, 0) ||
-		IsNaN(out.Theta) || IsInf(out.Theta, 0) || IsNaN(out.Rho) || IsInf(out.Rho, 0) {
-		return ErrPricing("Pricing error has occurred.")
-	}
-	// Scaling some of the Greeks based on market conventions.
-	out.Vega = out.Vega / 100.0
-	out.Theta = out.Theta / 365.0
-	out.Rho = out.Rho / 100.0
-	return nil
-}
-
-/*
-getGBSMValue is a Goroutine and is not accessible outside this package.
-It computes the theoretical value of a financial option using the
-Generalized Black Scholes Merton pricing model.
-*/
-func getGBSMValue(c chan float64, ot OptionType, d1 float64, d2 float64, s float64, k float64, t float64, r float64, b float64) {
-	switch ot {
-	case Call:
-		c <- (s * Exp((b-r)*t) * CDF(d1)) - (k * Exp((-r)*t) * CDF(d2))
-	case Put:
-		c <- (k * Exp((-r)*t) * CDF(-d2)) - (s * Exp((b-r)*t) * CDF(-d1))
-	}
-	close(c)
-}
-
-/*
-getGBSMDelta is a Goroutine and is not accessible outside this package.
-It computes the Delta of a financial option using the Generalized Black
-Scholes Merton pricing model.
-*/
-func getGBSMDelta(c chan float64, ot OptionType, d1 float64, t float64, r float64, b float64) {
-	switch ot {
-	case Call:
-		c <- Exp((b-r)*t) * CDF(d1)
-	case Put:
-		c <- Exp((b-r)*t) * (CDF(d1) - 1.0)
-	}
-	close(c)
-}
-
-/*
-getGBSMTheta is a Goroutine and is not accessible outside this package.
-It computes the Theta of a financial option using the Generalized Black
-Scholes Merton pricing model.
-*/
-func getGBSMTheta(c chan float64, ot OptionType, d1 float64, d2 float64, s float64, k float64, t float64, v float64, r float64, b float64) {
-	switch ot {
-	case Call:
-		c <- (((-s) * Exp((b-r)*t) * PDF(d1) * v) / (2.0 * Sqrt(t))) -
-			((b - r) * s * Exp((b-r)*t) * CDF(d1)) -
-			(r * k * Exp((-r)*t) * CDF(d2))
-	case Put:
-		c <- (((-s) * Exp((b-r)*t) * PDF(d1) * v) / (2.0 * Sqrt(t))) +
-			((b - r) * s * Exp((b-r)*t) * CDF(-d1)) +
-			(r * k * Exp((-r)*t) * CDF(-d2))
-	}
-	close(c)
+	return out.gbsmKernel("GBSM", ot, s, k, t, v, r, b, Sqrt(t), Exp((b-r)*t), Exp((-r)*t))
 }
 
 /*
-getGBSMRho is a Goroutine and is not accessible outside this package.
-It computes the Rho of a financial option using the Generalized Black
-Scholes Merton pricing model.
+gbsmKernel is an unexported method that computes the results of the GBSM
+method for the named pricer, given the square root of the time to expiry,
+the carry factor Exp((b-r)*t) and the discount factor Exp(-r*t), so that
+GBSMChain can reuse them across the options of an expiry. The value and
+all the greeks are computed sequentially from d1 and d2 and their
+densities, which are computed once.
 */
-func getGBSMRho(c chan float64, ot OptionType, d2 float64, k float64, t float64, r float64) {
-	switch ot {
-	case Call:
-		c <- t * k * Exp((-r)*t) * CDF(d2)
-	case Put:
-		c <- (-t) * k * Exp((-r)*t) * CDF(-d2)
-	}
-	close(c)
+func (out *ModelOutputs) gbsmKernel(pricer string, ot OptionType, s float64, k float64, t float64, v float64, r float64, b float64, sqrtT float64, carry float64, disc float64) error {
+	in, err := checkGBSMInputs(pricer, ot, s, k, t, v, r, b)
+	if err != nil {
+		return err
+	}
+	phi := vanillaSign(Vanilla{Type: ot, Strike: k})
+	vt := v * sqrtT
+	// At expiry or at zero volatility, d1 and d2 are undefined; take their limits instead.
+	if vt == 0.0 {
+		out.gbsmLimit(phi, s, k, t, r, b, sqrtT, carry, disc)
+		return out.finish(pricer, in, s)
+	}
+	// Compute d1 and d2 as specified by the pricing model.
+	d1 := (Log(s/k) + ((b + v*v/2.0) * t)) / vt
+	d2 := d1 - vt
+	n1, n2 := PDF(d1), PDF(d2)
+	gamma := (n1 * carry) / (s * vt)
+	vega := s * carry * n1 * sqrtT
+	decay := n1 * ((b / vt) - (d2 / (2.0 * t)))
+	// The put formulas are the call formulas with phi = -1.
+	cdf1, cdf2 := CDF(phi*d1), CDF(phi*d2)
+	out.Value = phi * ((s * carry * cdf1) - (k * disc * cdf2))
+	out.Delta = phi * carry * cdf1
+	out.Theta = (((-s) * carry * n1 * v) / (2.0 * sqrtT)) - (phi * (b - r) * s * carry * cdf1) - (phi * r * k * disc * cdf2)
+	out.Rho = phi * t * k * disc * cdf2
+	out.Charm = carry * (-decay - (phi * (b - r) * cdf1))
+	out.DualDelta = (-phi) * disc * cdf2
+	out.CarryRho = phi * t * s * carry * cdf1
+	out.Gamma = gamma
+	out.Vega = vega
+	out.Vanna = (-n1) * carry * d2 / v
+	out.Volga = vega * d1 * d2 / v
+	out.Speed = (-gamma) * (1.0 + (d1 / vt)) / s
+	out.Zomma = gamma * ((d1 * d2) - 1.0) / v
+	out.Color = gamma * ((r - b) + ((b * d1) / vt) + ((1.0 - (d1 * d2)) / (2.0 * t)))
+	out.Veta = vega * ((r - b) + ((b * d1) / vt) - ((1.0 + (d1 * d2)) / (2.0 * t)))
+	out.Ultima = vega * ((d1 * d2 * ((d1 * d2) - 1.0)) - (d1 * d1) - (d2 * d2)) / (v * v)
+	out.DualGamma = (n2 * disc) / (k * vt)
+	return out.finish(pricer, in, s)
 }
 
 /*
-getGBSMGamma is a Goroutine and is not accessible outside this package.
-It computes the Gamma of a financial option using the Generalized Black
-Scholes Merton pricing model.
+gbsmLimit is an unexported method that computes the results of the
+gbsmKernel method in the limit where v*Sqrt(t) is zero, i.e. at expiry or
+at zero volatility, for the sign phi of the payoff. The option is then
+worth the discounted intrinsic value of the forward, so the cumulative
+probabilities N(phi*d1) and N(phi*d2) become 1 if the forward is in the
+money, 0 if it is out of the money, and 1/2 if it is at the strike price.
+Gamma and the other greeks that depend on the density of d1 or d2 are
+zero, except for Vega at zero volatility, which remains positive at the
+money.
 */
-func getGBSMGamma(c chan float64, ot OptionType, d1 float64, s float64, t float64, v float64, r float64, b float64) {
-	c <- (PDF(d1) * Exp((b-r)*t)) / (s * v * Sqrt(t))
-	close(c)
+func (out *ModelOutputs) gbsmLimit(phi float64, s float64, k float64, t float64, r float64, b float64, sqrtT float64, carry float64, disc float64) {
+	x := phi * (Log(s/k) + b*t)
+	n := 0.5
+	switch {
+	case x > 0.0:
+		n = 1.0
+	case x < 0.0:
+		n = 0.0
+	}
+	*out = ModelOutputs{Conventions: out.Conventions}
+	out.Value = phi * ((s * carry) - (k * disc)) * n
+	out.Delta = phi * carry * n
+	out.Theta = (-phi) * (((b - r) * s * carry) + (r * k * disc)) * n
+	out.Rho = phi * t * k * disc * n
+	out.Charm = (-phi) * (b - r) * carry * n
+	out.DualDelta = (-phi) * disc * n
+	out.CarryRho = phi * t * s * carry * n
+	if x == 0.0 {
+		out.Vega = s * carry * PDF(0.0) * sqrtT
+	}
 }
 
 /*
-getGBSMVega is a Goroutine and is not accessible outside this package.
-It computes the Vega of a financial option using the Generalized Black
-Scholes Merton pricing model.
+finish is an unexported method that computes Lambda from the value and
+Delta in the fields of the ModelOutputs receiver, checks the results for
+pricing errors, and scales the greeks by its market conventions. It
+returns the same errors as check and scale.
 */
-func getGBSMVega(c chan float64, ot OptionType, d1 float64, s float64, t float64, r float64, b float64) {
-	c <- s * Exp((b-r)*t) * PDF(d1) * Sqrt(t)
-	close(c)
+func (out *ModelOutputs) finish(pricer string, in []field, s float64) error {
+	// Lambda is the elasticity of the option, and is zero for a worthless option.
+	out.Lambda = 0.0
+	if out.Value != 0.0 {
+		out.Lambda = out.Delta * s / out.Value
+	}
+	// Check for pricing error.
+	if err := out.check(pricer, in); err != nil {
+		return err
+	}
+	// Scaling some of the Greeks based on market conventions.
+	return out.scale()
 }
 
 /*
@@ -227,8 +190,8 @@ BS1973 -- Black and Scholes (1973) pricing model
 Description:
 A method that computes the theoretical value and greeks of an option
 on a stock that does not pay dividend, and saves the computed results
-in the fields of the ModelOutputs receiver. It returns the error
-ErrPricing if a pricing error has occurred; otherwise, it returns nil.
+in the fields of the ModelOutputs receiver. It returns the same errors
+as GBSM.
 
 Usage:
 var out analytical.ModelOutputs
@@ -260,8 +223,7 @@ Description:
 A method that computes the theoretical value and greeks of an option on
 a stock (or stock index) that pays a known continuous dividend yield, and
 saves the computed results in the fields of the ModelOutputs receiver. It
-returns the error ErrPricing if a pricing error has occurred; otherwise,
-it returns nil.
+returns the same errors as GBSM.
 
 Usage:
 var out analytical.ModelOutputs
@@ -293,8 +255,7 @@ B1976 -- Black (1976) pricing model
 Description:
 A method that computes the theoretical value and greeks of an option on a
 forward or futures contract, and saves the computed results in the fields
-of the ModelOutputs receiver. It returns the error ErrPricing if a pricing
-error has occurred; otherwise, it returns nil.
+of the ModelOutputs receiver. It returns the same errors as GBSM.
 
 Usage:
 var out analytical.ModelOutputs
@@ -318,6 +279,62 @@ func (out *ModelOutputs) B1976(ot OptionType, f float64, k float64, t float64, v
 	return nil
 }
 
+/*
+--------------------------------------------------------------------------
+B1976Shifted -- Shifted-lognormal Black (1976) pricing model
+
+Description:
+A method that computes the theoretical value and greeks of an option on a
+forward or futures contract whose price plus the shift follows a
+geometric Brownian motion (i.e. a displaced diffusion), and saves the
+computed results in the fields of the ModelOutputs receiver. The option
+is valued as the B1976 method does with the shifted forward and strike
+prices f+shift and k+shift, so the forward and strike prices may be zero
+or negative. The greeks are with respect to the unshifted forward and
+strike prices, and Lambda is the elasticity to the unshifted forward
+price. It returns an *InputError if f+shift or k+shift is not positive,
+or if any argument is not a finite number, and otherwise the same errors
+as GBSM.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.B1976Shifted(ot, f, k, t, v, r, shift)
+
+Arguments:
+ot    option type (either options.Call or options.Put from
+      the options package)
+f     forward price of the underlying instrument
+k     strike price of the option
+t     time to expiry of the option
+v     lognormal volatility of the shifted forward price
+r     risk-free rate
+shift shift of the forward and strike prices
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) B1976Shifted(ot OptionType, f float64, k float64, t float64, v float64, r float64, shift float64) error {
+	// Validate the unshifted inputs before computing anything.
+	in := []field{{"ot", float64(ot)}, {"f", f}, {"k", k}, {"t", t}, {"v", v}, {"r", r}, {"shift", shift}}
+	if err := checkInputs("B1976Shifted", in); err != nil {
+		return err
+	}
+	if f+shift <= 0.0 {
+		return &InputError{"B1976Shifted", "f", f, "must be above -shift", inputMap(in)}
+	}
+	if k+shift <= 0.0 {
+		return &InputError{"B1976Shifted", "k", k, "must be above -shift", inputMap(in)}
+	}
+	err := out.B1976(ot, f+shift, k+shift, t, v, r)
+	if err != nil {
+		return err
+	}
+	// The shift is a constant, so only Lambda depends on the unshifted forward price.
+	out.Lambda = 0.0
+	if out.Value != 0.0 {
+		out.Lambda = out.Delta * f / out.Value
+	}
+	return nil
+}
+
 /*
 ------------------------------------------------------------------------
 A1982 -- Asay (1982) pricing model
@@ -326,8 +343,7 @@ Description:
 A method that computes the theoretical value and greeks of an option
 on a futures contract where the premium is fully margined, and saves
 the computed results in the fields of the ModelOutputs receiver. It
-returns the error ErrPricing if a pricing error has occurred; otherwise,
-it returns nil.
+returns the same errors as GBSM.
 
 Usage:
 var out analytical.ModelOutputs
@@ -357,8 +373,7 @@ GK1983 -- Garman and Kohlhagen (1983)
 Description:
 A method that computes the theoretical value and greeks of a currency
 option, and saves the computed results in the fields of the ModelOutputs
-receiver. It returns the error ErrPricing if a pricing error has occurred;
-otherwise, it returns nil.
+receiver. It returns the same errors as GBSM.
 
 Usage:
 var out analytical.ModelOutputs
@@ -391,8 +406,7 @@ Description:
 A method that computes the theoretical value and greeks of an option
 on a stock that pays discrete cash dividends, and saves the computed
 results in the fields of the ModelOutputs receiver. It returns the
-error ErrPricing if a pricing error has occurred; otherwise, it
-returns nil.
+same errors as BV2002Curves.
 
 Usage:
 var out analytical.ModelOutputs
@@ -411,24 +425,151 @@ dl discrete dividend list (the equity.DivList type
 --------------------------------------------------------------------
 */
 func (out *ModelOutputs) BV2002(ot OptionType, s float64, k float64, t float64, v float64, r float64, dl DivList) error {
-	adjS := s - divNear(r, t, dl)
-	adjK := k + (divFar(r, t, dl) * Exp(r*t))
-	err := out.BS1973(ot, adjS, adjK, t, v, r)
+	err := out.BV2002Curves(ot, s, k, t, v, curves.FlatCurve{Rate: r}, dl)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+/*
+--------------------------------------------------------------------------
+GBSMCurves -- Generalized Black Scholes Merton pricing model on curves
+
+Description:
+A method that computes the theoretical value and greeks of a financial
+option as the GBSM method does, with the risk-free rate and the cost of
+carry taken as the zero rates to expiry of the given curves, and saves the
+computed results in the fields of the ModelOutputs receiver. It returns an
+*InputError if rc or bc is nil, or otherwise the same errors as GBSM, with
+the zero rates as r and b.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.GBSMCurves(ot, s, k, t, v, rc, bc)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+v  volatility of the underlying instrument
+rc risk-free curve (any type that implements the
+   curves.DiscountCurve interface in the curves package)
+bc cost of carry curve (any type that implements the
+   curves.DiscountCurve interface in the curves package)
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) GBSMCurves(ot OptionType, s float64, k float64, t float64, v float64, rc curves.DiscountCurve, bc curves.DiscountCurve) error {
+	in := []field{{"ot", float64(ot)}, {"s", s}, {"k", k}, {"t", t}, {"v", v}}
+	if rc == nil {
+		return &InputError{"GBSM", "rc", NaN(), "must not be nil", inputMap(in)}
+	}
+	if bc == nil {
+		return &InputError{"GBSM", "bc", NaN(), "must not be nil", inputMap(in)}
+	}
+	err := out.GBSM(ot, s, k, t, v, rc.ZeroRate(t), bc.ZeroRate(t))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+/*
+--------------------------------------------------------------------
+BV2002Curves -- Bos and Vandermark (2002) pricing model on a curve
+
+Description:
+A method that computes the theoretical value and greeks of an option
+on a stock that pays discrete cash dividends as the BV2002 method
+does, with each dividend discounted on the given risk-free curve and
+the risk-free rate taken as its zero rate to expiry, and saves the
+computed results in the fields of the ModelOutputs receiver. The
+inputs are validated before the option is priced. It returns an
+*InputError if rc is nil, if the other arguments are invalid as for
+GBSM, or if a dividend has a negative amount; an *ArbitrageError if
+the present value of the dividends is at or above the spot price; or
+otherwise the same errors as GBSM.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.BV2002Curves(ot, s, k, t, v, rc, dl)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+v  volatility of the underlying instrument
+rc risk-free curve (any type that implements the
+   curves.DiscountCurve interface in the curves package)
+dl discrete dividend list (the equity.DivList type
+   in the equity package)
+--------------------------------------------------------------------
+*/
+func (out *ModelOutputs) BV2002Curves(ot OptionType, s float64, k float64, t float64, v float64, rc curves.DiscountCurve, dl DivList) error {
+	// Validate the inputs before computing anything.
+	in, err := checkBV2002Inputs(ot, s, k, t, v, rc, dl)
+	if err != nil {
+		return err
+	}
+	adjS := s - divNear(rc, t, dl)
+	adjK := k + (divFar(rc, t, dl) / rc.DiscountFactor(t))
+	if adjS <= 0.0 {
+		in = append(in, field{"adjS", adjS})
+		return &ArbitrageError{"BV2002", "The present value of the dividends is at or above the spot price.", inputMap(in)}
+	}
+	err = out.BS1973(ot, adjS, adjK, t, v, rc.ZeroRate(t))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+/*
+checkBV2002Inputs is an unexported function that returns the arguments of
+BV2002Curves, with the zero rate of the curve to expiry as r, and an
+*InputError if the curve is nil, if the other arguments are invalid as for
+GBSM, or if a dividend has a time that is not a finite number or an amount
+that is negative or not a finite number.
+*/
+func checkBV2002Inputs(ot OptionType, s float64, k float64, t float64, v float64, rc curves.DiscountCurve, dl DivList) ([]field, error) {
+	in := []field{{"ot", float64(ot)}, {"s", s}, {"k", k}, {"t", t}, {"v", v}}
+	for i, d := range dl {
+		td, amount := DestructDiv(d)
+		in = append(in, field{fmt.Sprintf("dl[%d].TimeToDividend", i), td}, field{fmt.Sprintf("dl[%d].Amount", i), amount})
+	}
+	if rc == nil {
+		return in, &InputError{"BV2002", "rc", NaN(), "must not be nil", inputMap(in)}
+	}
+	in = append(in, field{"r", rc.ZeroRate(t)})
+	if err := checkOptionType("BV2002", ot, in); err != nil {
+		return in, err
+	}
+	if err := checkInputs("BV2002", in, "s", "k"); err != nil {
+		return in, err
+	}
+	names := []string{"t", "v"}
+	for i := range dl {
+		names = append(names, fmt.Sprintf("dl[%d].Amount", i))
+	}
+	return in, checkNonNegative("BV2002", in, names...)
+}
+
 /*
 divNear is an unexported function that returns the weighted
 present value of 'near' dividends.
 */
-func divNear(r float64, tte float64, dl DivList) float64 {
+func divNear(rc curves.DiscountCurve, tte float64, dl DivList) float64 {
 	t, d, dn := 0.0, 0.0, 0.0
+	if tte <= 0.0 {
+		return dn
+	}
 	for _, v := range dl {
 		if t, d = DestructDiv(v); t <= tte {
-			dn += (tte - t) / tte * d * Exp((-r)*t)
+			dn += (tte - t) / tte * d * rc.DiscountFactor(t)
 		}
 	}
 	return dn
@@ -438,11 +579,14 @@ func divNear(r float64, tte float64, dl DivList) float64 {
 divFar is an unexported function that returns the weighted
 present value of 'far' dividends.
 */
-func divFar(r float64, tte float64, dl DivList) float64 {
+func divFar(rc curves.DiscountCurve, tte float64, dl DivList) float64 {
 	t, d, df := 0.0, 0.0, 0.0
+	if tte <= 0.0 {
+		return df
+	}
 	for _, v := range dl {
 		if t, d = DestructDiv(v); t <= tte {
-			df += t / tte * d * Exp((-r)*t)
+			df += t / tte * d * rc.DiscountFactor(t)
 		}
 	}
 	return df
@@ -0,0 +1,286 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"errors"
+	"github.com/kervinlow/quantstruct/curves"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+	"testing"
+)
+
+func TestGBSMCurvesFlat(t *testing.T) {
+	var want, out ModelOutputs
+	if err := want.GBSM(Call, 60.0, 65.0, 0.25, 0.30, 0.08, 0.05); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.GBSMCurves(Call, 60.0, 65.0, 0.25, 0.30, curves.FlatCurve{Rate: 0.08}, curves.FlatCurve{Rate: 0.05}); err != nil {
+		t.Fatal(err)
+	}
+	if Abs(out.Value-want.Value) > 1.0e-12 || Abs(out.Delta-want.Delta) > 1.0e-12 {
+		t.Errorf("GBSMCurves = %v (delta %v), want %v (delta %v)", out.Value, out.Delta, want.Value, want.Delta)
+	}
+}
+
+func TestGBSMCurvesNonFlat(t *testing.T) {
+	times := []float64{0.25, 0.5, 1.0, 2.0}
+	rc, err := curves.NewMonotoneConvexCurve(times, []float64{0.03, 0.035, 0.04, 0.045})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc, err := curves.NewLinearZeroCurve(times, []float64{0.01, 0.015, 0.025, 0.03})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tte := range []float64{0.3, 0.75, 1.5} {
+		var call, put, want ModelOutputs
+		if err := call.GBSMCurves(Call, 100.0, 105.0, tte, 0.25, rc, bc); err != nil {
+			t.Fatal(err)
+		}
+		if err := put.GBSMCurves(Put, 100.0, 105.0, tte, 0.25, rc, bc); err != nil {
+			t.Fatal(err)
+		}
+		// The rates are the zero rates of the curves to expiry.
+		if err := want.GBSM(Call, 100.0, 105.0, tte, 0.25, rc.ZeroRate(tte), bc.ZeroRate(tte)); err != nil {
+			t.Fatal(err)
+		}
+		if Abs(call.Value-want.Value) > 1.0e-12 {
+			t.Errorf("GBSMCurves(t=%v) = %v, want %v", tte, call.Value, want.Value)
+		}
+		// The put-call parity holds with the discount factors of the curves.
+		fwd := 100.0*rc.DiscountFactor(tte)/bc.DiscountFactor(tte) - 105.0*rc.DiscountFactor(tte)
+		if Abs(call.Value-put.Value-fwd) > 1.0e-10 {
+			t.Errorf("GBSMCurves(t=%v) call - put = %v, want %v", tte, call.Value-put.Value, fwd)
+		}
+	}
+}
+
+func TestGBSMCurvesNilCurve(t *testing.T) {
+	var out ModelOutputs
+	var ie *InputError
+	err := out.GBSMCurves(Call, 60.0, 65.0, 0.25, 0.30, nil, curves.FlatCurve{Rate: 0.05})
+	if !errors.As(err, &ie) || ie.Field != "rc" {
+		t.Errorf("GBSMCurves with a nil risk-free curve returned %v, want an *InputError for rc", err)
+	}
+	err = out.GBSMCurves(Call, 60.0, 65.0, 0.25, 0.30, curves.FlatCurve{Rate: 0.08}, nil)
+	if !errors.As(err, &ie) || ie.Field != "bc" {
+		t.Errorf("GBSMCurves with a nil cost of carry curve returned %v, want an *InputError for bc", err)
+	}
+}
+
+func TestBV2002Curves(t *testing.T) {
+	rc, err := curves.NewLinearZeroCurve([]float64{0.25, 0.5, 1.0}, []float64{0.03, 0.04, 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dl := DivList{}.AddDiv(0.2, 1.0).AddDiv(0.7, 1.5)
+	var out, want ModelOutputs
+	if err := out.BV2002Curves(Call, 100.0, 100.0, 1.0, 0.3, rc, dl); err != nil {
+		t.Fatal(err)
+	}
+	// Each dividend is split between the spot and the strike prices in
+	// proportion to its time, and discounted on the curve.
+	adjS := 100.0 - 0.8*1.0*rc.DiscountFactor(0.2) - 0.3*1.5*rc.DiscountFactor(0.7)
+	adjK := 100.0 + (0.2*1.0*rc.DiscountFactor(0.2)+0.7*1.5*rc.DiscountFactor(0.7))/rc.DiscountFactor(1.0)
+	if err := want.BS1973(Call, adjS, adjK, 1.0, 0.3, rc.ZeroRate(1.0)); err != nil {
+		t.Fatal(err)
+	}
+	if Abs(out.Value-want.Value) > 1.0e-12 {
+		t.Errorf("BV2002Curves = %v, want %v", out.Value, want.Value)
+	}
+	// Without dividends, the option is valued as by GBSMCurves with the cost of
+	// carry equal to the risk-free rate.
+	if err := out.BV2002Curves(Put, 100.0, 95.0, 0.75, 0.3, rc, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := want.GBSMCurves(Put, 100.0, 95.0, 0.75, 0.3, rc, rc); err != nil {
+		t.Fatal(err)
+	}
+	if Abs(out.Value-want.Value) > 1.0e-12 {
+		t.Errorf("BV2002Curves without dividends = %v, want %v", out.Value, want.Value)
+	}
+}
+
+func TestGBSMHaug(t *testing.T) {
+	// The Black and Scholes (1973) call example of Haug (2007), The
+	// Complete Guide to Option Pricing Formulas, 2nd edition.
+	var call, put ModelOutputs
+	if err := call.GBSM(Call, 60.0, 65.0, 0.25, 0.30, 0.08, 0.08); err != nil {
+		t.Fatal(err)
+	}
+	if Abs(call.Value-2.1334) > 5.0e-5 {
+		t.Errorf("GBSM(Call) = %.4f, want 2.1334", call.Value)
+	}
+	// Put-call parity holds for the value and the first-order greeks.
+	if err := put.GBSM(Put, 60.0, 65.0, 0.25, 0.30, 0.08, 0.08); err != nil {
+		t.Fatal(err)
+	}
+	fwd := 60.0 - 65.0*Exp(-0.08*0.25)
+	if Abs(call.Value-put.Value-fwd) > 1.0e-12 || Abs(call.Delta-put.Delta-1.0) > 1.0e-12 || call.Gamma != put.Gamma {
+		t.Errorf("GBSM violates put-call parity: call %v (delta %v), put %v (delta %v)", call.Value, call.Delta, put.Value, put.Delta)
+	}
+}
+
+func TestGBSMInvalidOptionType(t *testing.T) {
+	var out ModelOutputs
+	var ie *InputError
+	if err := out.GBSM(OptionType(2), 60.0, 65.0, 0.25, 0.30, 0.08, 0.08); !errors.As(err, &ie) || ie.Field != "ot" {
+		t.Errorf("GBSM with an invalid option type returned %v, want an *InputError for ot", err)
+	}
+	var e ErrInvalidPayoff
+	if err := out.GBSMPayoff(Vanilla{Type: OptionType(2), Strike: 65.0}, 60.0, 0.25, 0.30, 0.08, 0.08); !errors.As(err, &e) {
+		t.Errorf("GBSMPayoff with an invalid option type returned %v, want ErrInvalidPayoff", err)
+	}
+}
+
+func TestGBSMGreeksFiniteDifference(t *testing.T) {
+	const s, k, tte, v, r, b = 100.0, 95.0, 0.75, 0.25, 0.06, 0.02
+	for name, ot := range map[string]OptionType{"Call": Call, "Put": Put} {
+		// price returns the results of GBSM with the inputs bumped by the given amounts.
+		price := func(ds, dk, dt, dv, db float64) ModelOutputs {
+			var out ModelOutputs
+			if err := out.GBSM(ot, s+ds, k+dk, tte+dt, v+dv, r, b+db); err != nil {
+				t.Fatal(err)
+			}
+			return out
+		}
+		out := price(0.0, 0.0, 0.0, 0.0, 0.0)
+		// Each greek is compared with the central difference of the value or of a
+		// lower-order greek, with the market scaling of GBSM undone.
+		const hs, hk, ht, hv, hb = 1.0e-3, 1.0e-3, 1.0e-5, 1.0e-5, 1.0e-5
+		cases := []struct {
+			name string
+			got  float64
+			want float64
+		}{
+			{"Vanna", out.Vanna * 100.0, (price(0, 0, 0, hv, 0).Delta - price(0, 0, 0, -hv, 0).Delta) / (2.0 * hv)},
+			{"Volga", out.Volga * 10000.0, (price(0, 0, 0, hv, 0).Vega - price(0, 0, 0, -hv, 0).Vega) * 100.0 / (2.0 * hv)},
+			{"Charm", out.Charm * 365.0, -(price(0, 0, ht, 0, 0).Delta - price(0, 0, -ht, 0, 0).Delta) / (2.0 * ht)},
+			{"Speed", out.Speed, (price(hs, 0, 0, 0, 0).Gamma - price(-hs, 0, 0, 0, 0).Gamma) / (2.0 * hs)},
+			{"Zomma", out.Zomma * 100.0, (price(0, 0, 0, hv, 0).Gamma - price(0, 0, 0, -hv, 0).Gamma) / (2.0 * hv)},
+			{"Color", out.Color * 365.0, -(price(0, 0, ht, 0, 0).Gamma - price(0, 0, -ht, 0, 0).Gamma) / (2.0 * ht)},
+			{"Veta", out.Veta * 100.0 * 365.0, -(price(0, 0, ht, 0, 0).Vega - price(0, 0, -ht, 0, 0).Vega) * 100.0 / (2.0 * ht)},
+			{"Ultima", out.Ultima * 1000000.0, (price(0, 0, 0, hv, 0).Volga - price(0, 0, 0, -hv, 0).Volga) * 10000.0 / (2.0 * hv)},
+			{"DualDelta", out.DualDelta, (price(0, hk, 0, 0, 0).Value - price(0, -hk, 0, 0, 0).Value) / (2.0 * hk)},
+			{"DualGamma", out.DualGamma, (price(0, hk, 0, 0, 0).Value - 2.0*out.Value + price(0, -hk, 0, 0, 0).Value) / (hk * hk)},
+			{"CarryRho", out.CarryRho * 100.0, (price(0, 0, 0, 0, hb).Value - price(0, 0, 0, 0, -hb).Value) / (2.0 * hb)},
+		}
+		for _, c := range cases {
+			if Abs(c.got-c.want) > 1.0e-5*Max(1.0, Abs(c.want)) {
+				t.Errorf("GBSM(%s) %s = %v, want %v", name, c.name, c.got, c.want)
+			}
+		}
+	}
+}
+
+func TestGBSMLimit(t *testing.T) {
+	const k, r, b = 100.0, 0.05, 0.02
+	cases := []struct {
+		ot        OptionType
+		s         float64
+		wantDelta float64
+	}{
+		{Call, 90.0, 0.0},
+		{Call, 100.0, 0.5},
+		{Call, 110.0, 1.0},
+		{Put, 90.0, -1.0},
+		{Put, 100.0, -0.5},
+		{Put, 110.0, 0.0},
+	}
+	// At expiry, the option is worth its intrinsic value.
+	for _, c := range cases {
+		var out ModelOutputs
+		if err := out.GBSM(c.ot, c.s, k, 0.0, 0.25, r, b); err != nil {
+			t.Fatal(err)
+		}
+		want := Max(c.s-k, 0.0)
+		if c.ot == Put {
+			want = Max(k-c.s, 0.0)
+		}
+		if out.Value != want || out.Delta != c.wantDelta {
+			t.Errorf("GBSM %v at expiry with s = %v: Value, Delta = %v, %v, want %v, %v", c.ot, c.s, out.Value, out.Delta, want, c.wantDelta)
+		}
+		if out.Gamma != 0.0 || out.Vega != 0.0 {
+			t.Errorf("GBSM %v at expiry with s = %v: Gamma, Vega = %v, %v, want 0, 0", c.ot, c.s, out.Gamma, out.Vega)
+		}
+	}
+	// At zero volatility, the option is worth the discounted intrinsic value
+	// of the forward, which is the spot price at a zero cost of carry.
+	const tte = 0.5
+	disc := Exp((-r) * tte)
+	for _, c := range cases {
+		var out ModelOutputs
+		if err := out.GBSM(c.ot, c.s, k, tte, 0.0, r, 0.0); err != nil {
+			t.Fatal(err)
+		}
+		want := disc * Max(c.s-k, 0.0)
+		if c.ot == Put {
+			want = disc * Max(k-c.s, 0.0)
+		}
+		if Abs(out.Value-want) > 1e-12 || Abs(out.Delta-disc*c.wantDelta) > 1e-12 {
+			t.Errorf("GBSM %v at zero volatility with a forward of %v: Value, Delta = %v, %v, want %v, %v", c.ot, c.s, out.Value, out.Delta, want, disc*c.wantDelta)
+		}
+		if out.Gamma != 0.0 {
+			t.Errorf("GBSM %v at zero volatility with a forward of %v: Gamma = %v, want 0", c.ot, c.s, out.Gamma)
+		}
+		if atm := c.s == k; (out.Vega > 0.0) != atm {
+			t.Errorf("GBSM %v at zero volatility with a forward of %v: Vega = %v, want it positive only at the money", c.ot, c.s, out.Vega)
+		}
+	}
+}
+
+func TestB1976ShiftedZeroShift(t *testing.T) {
+	for _, ot := range []OptionType{Call, Put} {
+		for _, k := range []float64{0.02, 0.03, 0.045} {
+			var want, got ModelOutputs
+			if err := want.B1976(ot, 0.03, k, 2.0, 0.25, 0.02); err != nil {
+				t.Fatal(err)
+			}
+			if err := got.B1976Shifted(ot, 0.03, k, 2.0, 0.25, 0.02, 0.0); err != nil {
+				t.Fatal(err)
+			}
+			// Lambda is recomputed from the unshifted forward price, so it may
+			// differ in the last digit.
+			if Abs(got.Lambda-want.Lambda) > 1.0e-12*Abs(want.Lambda) {
+				t.Errorf("B1976Shifted(%v, k=%v, shift=0) Lambda = %v, want %v", ot, k, got.Lambda, want.Lambda)
+			}
+			got.Lambda = want.Lambda
+			if got != want {
+				t.Errorf("B1976Shifted(%v, k=%v, shift=0) = %+v, want B1976 = %+v", ot, k, got, want)
+			}
+			vol, err := B1976ShiftedImpliedVol(ot, want.Value, 0.03, k, 2.0, 0.02, 0.0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if Abs(vol-0.25) > 1.0e-10 {
+				t.Errorf("B1976ShiftedImpliedVol(%v, k=%v, shift=0) = %v, want 0.25", ot, k, vol)
+			}
+		}
+	}
+}
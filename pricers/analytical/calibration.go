@@ -0,0 +1,289 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"fmt"
+	. "github.com/kervinlow/quantstruct/math"
+	. "math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidCalibration is returned when the market quotes or the
+settings of a calibration are invalid.
+*/
+type ErrInvalidCalibration string
+
+func (e ErrInvalidCalibration) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+================================================================
+Provides the calibration of the Heston (1993) pricing model to
+the implied volatilities of European options.
+================================================================
+*/
+
+/*
+HestonParams is the structure that holds the parameters of the Heston
+(1993) pricing model, as taken by the H1993 method.
+*/
+type HestonParams struct {
+	V0    float64 // initial variance
+	Kappa float64 // rate of mean reversion of the variance
+	Theta float64 // long-run variance
+	Sigma float64 // volatility of the variance
+	Rho   float64 // correlation between the underlying price and its variance
+}
+
+/*
+VolQuote is the structure that holds the market quote of a European
+option, as its implied volatility: lognormal (GBSM) for CalibrateH1993,
+and lognormal or normal, by the formula, for CalibrateH2002.
+*/
+type VolQuote struct {
+	Strike float64
+	Expiry float64
+	Vol    float64
+}
+
+/*
+HestonCalibrationConfig is the structure that holds the settings of a
+calibration of the Heston (1993) pricing model. The parameters are kept
+within the bounds Lower and Upper; a parameter is held fixed at its bound
+if both bounds are equal. When Feller is set, Sigma is also kept at or
+below Sqrt(2*Kappa*Theta), so that the variance stays positive, and this
+takes precedence over the lower bound of Sigma. Tolerance and
+MaxIterations are passed to the math.LevenbergMarquardt optimizer.
+
+Usage (example):
+var cfg = analytical.DefaultHestonCalibrationConfig()
+cfg.Feller = true
+*/
+type HestonCalibrationConfig struct {
+	Lower         HestonParams
+	Upper         HestonParams
+	Feller        bool
+	Tolerance     float64
+	MaxIterations int
+}
+
+/*
+DefaultHestonCalibrationConfig returns the default settings of a
+calibration of the Heston (1993) pricing model, with wide bounds on the
+parameters and without the Feller condition.
+*/
+func DefaultHestonCalibrationConfig() HestonCalibrationConfig {
+	return HestonCalibrationConfig{
+		Lower:         HestonParams{V0: 1.0e-4, Kappa: 1.0e-2, Theta: 1.0e-4, Sigma: 1.0e-2, Rho: -0.999},
+		Upper:         HestonParams{V0: 1.0, Kappa: 20.0, Theta: 1.0, Sigma: 5.0, Rho: 0.999},
+		Tolerance:     1.0e-10,
+		MaxIterations: 200,
+	}
+}
+
+/*
+QuoteResidual is the structure that holds the fit of a calibrated model to
+a market quote: ModelVol is the GBSM implied volatility of the model price,
+and Residual is ModelVol less the quoted Vol.
+*/
+type QuoteResidual struct {
+	VolQuote
+	ModelVol float64
+	Residual float64
+}
+
+/*
+HestonFit is the structure that holds the result of a calibration of the
+Heston (1993) pricing model: the calibrated parameters, the residuals in
+the order of the market quotes, and the root mean square of the residuals.
+*/
+type HestonFit struct {
+	Params    HestonParams
+	Residuals []QuoteResidual
+	RMSE      float64
+}
+
+/*
+--------------------------------------------------------------------------
+CalibrateH1993 -- Calibration of the Heston (1993) pricing model
+
+Description:
+A function that finds the parameters of the Heston (1993) pricing model
+that best fit the given market quotes of European options on an
+underlying instrument with the spot price s, the risk-free rate r and the
+cost of carry b. Each quote is valued as the out-of-the-money option, and
+the differences between the model and market prices, divided by the
+market Vega, are minimized in the least-squares sense with the
+Levenberg-Marquardt method from the initial guess, within the bounds of
+the settings. The calibration is deterministic for given arguments. The
+residual of a quote whose model price has no implied volatility is taken
+to first order from its Vega. It returns an *InputError if s is not
+positive or any of s, r and b is not a finite number, the error
+ErrInvalidCalibration if there are no quotes, if a quote is not positive
+or finite, or if the guess or the settings are invalid, or the error
+ErrNoConvergence, together with the best fit found, if the optimizer does
+not converge within the maximum number of iterations; otherwise, it
+returns nil.
+
+Usage:
+var fit, err = analytical.CalibrateH1993(quotes, s, r, b, guess, cfg)
+
+Arguments:
+quotes market quotes of European options (a slice of
+       analytical.VolQuote)
+s      spot price of the underlying instrument
+r      risk-free rate
+b      cost of carry
+guess  initial guess of the parameters (the
+       analytical.HestonParams type)
+cfg    settings of the calibration (the
+       analytical.HestonCalibrationConfig type)
+--------------------------------------------------------------------------
+*/
+func CalibrateH1993(quotes []VolQuote, s float64, r float64, b float64, guess HestonParams, cfg HestonCalibrationConfig) (HestonFit, error) {
+	if err := checkInputs("CalibrateH1993", []field{{"s", s}, {"r", r}, {"b", b}}, "s"); err != nil {
+		return HestonFit{}, err
+	}
+	if err := cfg.validate(guess); err != nil {
+		return HestonFit{}, err
+	}
+	if len(quotes) == 0 {
+		return HestonFit{}, ErrInvalidCalibration("There are no market quotes.")
+	}
+	// Market prices and Vegas of the out-of-the-money options.
+	prices, vegas := make([]float64, len(quotes)), make([]float64, len(quotes))
+	for i, q := range quotes {
+		if !(q.Strike > 0.0 && q.Expiry > 0.0 && q.Vol > 0.0) || IsInf(q.Strike, 0) || IsInf(q.Expiry, 0) || IsInf(q.Vol, 0) {
+			return HestonFit{}, ErrInvalidCalibration(fmt.Sprintf("Market quote %d is not positive and finite.", i))
+		}
+		var out ModelOutputs
+		if err := out.GBSM(otmType(s*Exp(b*q.Expiry), q.Strike), s, q.Strike, q.Expiry, q.Vol, r, b); err != nil {
+			return HestonFit{}, err
+		}
+		prices[i], vegas[i] = out.Value, Max(out.Raw().Vega, 1.0e-8*s)
+	}
+	residuals := func(x []float64) []float64 {
+		p := cfg.params(x)
+		res := make([]float64, len(quotes))
+		for i, q := range quotes {
+			ot := otmType(s*Exp(b*q.Expiry), q.Strike)
+			value := getH1993Value(ot, s, q.Strike, q.Expiry, p.V0, r, b, p.Kappa, p.Theta, p.Sigma, p.Rho)
+			res[i] = (value - prices[i]) / vegas[i]
+		}
+		return res
+	}
+	lower, upper := cfg.bounds()
+	x, err := LevenbergMarquardt(residuals, cfg.vector(guess), lower, upper, cfg.Tolerance, cfg.MaxIterations)
+	if err != nil {
+		err = ErrNoConvergence("Calibration did not converge.")
+	}
+	// Report the fit in terms of implied volatility.
+	fit := HestonFit{Params: cfg.params(x), Residuals: make([]QuoteResidual, len(quotes))}
+	res := residuals(x)
+	var sum float64
+	for i, q := range quotes {
+		ot := otmType(s*Exp(b*q.Expiry), q.Strike)
+		value := prices[i] + (res[i] * vegas[i]) // the model price
+		vol, e := GBSMImpliedVol(ot, value, s, q.Strike, q.Expiry, r, b)
+		if e != nil {
+			vol = q.Vol + res[i]
+		}
+		fit.Residuals[i] = QuoteResidual{q, vol, vol - q.Vol}
+		sum += (vol - q.Vol) * (vol - q.Vol)
+	}
+	fit.RMSE = Sqrt(sum / float64(len(quotes)))
+	return fit, err
+}
+
+/*
+validate is an unexported method that returns the error
+ErrInvalidCalibration if the settings of the calibration or the initial
+guess are invalid; otherwise, it returns nil.
+*/
+func (cfg HestonCalibrationConfig) validate(guess HestonParams) error {
+	lo, hi, x := cfg.Lower, cfg.Upper, guess
+	for _, v := range []float64{lo.V0, lo.Kappa, lo.Theta, lo.Sigma, lo.Rho, hi.V0, hi.Kappa, hi.Theta, hi.Sigma, hi.Rho, x.V0, x.Kappa, x.Theta, x.Sigma, x.Rho} {
+		if IsNaN(v) || IsInf(v, 0) {
+			return ErrInvalidCalibration("The bounds and the guess must be finite numbers.")
+		}
+	}
+	if lo.V0 < 0.0 || lo.Kappa <= 0.0 || lo.Theta < 0.0 || lo.Sigma <= 0.0 || lo.Rho < -1.0 || hi.Rho > 1.0 {
+		return ErrInvalidCalibration("The bounds admit invalid parameters.")
+	}
+	if lo.V0 > hi.V0 || lo.Kappa > hi.Kappa || lo.Theta > hi.Theta || lo.Sigma > hi.Sigma || lo.Rho > hi.Rho {
+		return ErrInvalidCalibration("The lower bounds must not be above the upper bounds.")
+	}
+	if cfg.Feller && lo.Theta <= 0.0 {
+		return ErrInvalidCalibration("The lower bound of Theta must be positive with the Feller condition.")
+	}
+	if !(cfg.Tolerance > 0.0) || cfg.MaxIterations < 1 {
+		return ErrInvalidCalibration("The tolerance and the maximum number of iterations must be positive.")
+	}
+	return nil
+}
+
+/*
+vector, bounds and params are unexported methods that map the parameters
+of the Heston (1993) pricing model to and from the vector that is
+optimized. The vector holds V0, Kappa, Theta, Sigma and Rho, except that,
+when Feller is set, Sigma is replaced by its fraction of Sqrt(2*Kappa*Theta),
+between 0 and 1.
+*/
+func (cfg HestonCalibrationConfig) vector(p HestonParams) []float64 {
+	x := []float64{p.V0, p.Kappa, p.Theta, p.Sigma, p.Rho}
+	if cfg.Feller {
+		x[3] = p.Sigma / Sqrt(2.0*Max(p.Kappa, cfg.Lower.Kappa)*Max(p.Theta, cfg.Lower.Theta))
+	}
+	return x
+}
+
+func (cfg HestonCalibrationConfig) bounds() ([]float64, []float64) {
+	lo, hi := cfg.Lower, cfg.Upper
+	lower := []float64{lo.V0, lo.Kappa, lo.Theta, lo.Sigma, lo.Rho}
+	upper := []float64{hi.V0, hi.Kappa, hi.Theta, hi.Sigma, hi.Rho}
+	if cfg.Feller {
+		lower[3], upper[3] = 0.0, 1.0
+	}
+	return lower, upper
+}
+
+func (cfg HestonCalibrationConfig) params(x []float64) HestonParams {
+	p := HestonParams{V0: x[0], Kappa: x[1], Theta: x[2], Sigma: x[3], Rho: x[4]}
+	if cfg.Feller {
+		feller := Sqrt(2.0 * p.Kappa * p.Theta)
+		p.Sigma = Min(Min(Max(x[3]*feller, cfg.Lower.Sigma), cfg.Upper.Sigma), feller)
+	}
+	return p
+}
@@ -0,0 +1,90 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "math"
+	"testing"
+)
+
+/*
+hestonQuotes returns the GBSM implied volatilities of the H1993 prices
+with the given parameters, on a grid of strikes and expiries.
+*/
+func hestonQuotes(t *testing.T, p HestonParams, s float64, r float64, b float64) []VolQuote {
+	var quotes []VolQuote
+	for _, expiry := range []float64{0.25, 1.0, 2.0} {
+		for _, k := range []float64{80.0, 90.0, 100.0, 110.0, 120.0} {
+			ot := otmType(s*Exp(b*expiry), k)
+			var out ModelOutputs
+			if err := out.H1993(ot, s, k, expiry, p.V0, r, b, p.Kappa, p.Theta, p.Sigma, p.Rho); err != nil {
+				t.Fatal(err)
+			}
+			vol, err := GBSMImpliedVol(ot, out.Value, s, k, expiry, r, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			quotes = append(quotes, VolQuote{Strike: k, Expiry: expiry, Vol: vol})
+		}
+	}
+	return quotes
+}
+
+func TestCalibrateH1993RecoversParams(t *testing.T) {
+	want := HestonParams{V0: 0.04, Kappa: 1.5, Theta: 0.06, Sigma: 0.5, Rho: -0.7}
+	quotes := hestonQuotes(t, want, 100.0, 0.03, 0.01)
+	guess := HestonParams{V0: 0.02, Kappa: 3.0, Theta: 0.03, Sigma: 0.3, Rho: -0.3}
+	fit, err := CalibrateH1993(quotes, 100.0, 0.03, 0.01, guess, DefaultHestonCalibrationConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := fit.Params
+	if Abs(got.V0-want.V0) > 1.0e-8 || Abs(got.Kappa-want.Kappa) > 1.0e-6 || Abs(got.Theta-want.Theta) > 1.0e-8 ||
+		Abs(got.Sigma-want.Sigma) > 1.0e-6 || Abs(got.Rho-want.Rho) > 1.0e-6 {
+		t.Errorf("CalibrateH1993 = %+v, want %+v", got, want)
+	}
+	if fit.RMSE > 1.0e-10 || len(fit.Residuals) != len(quotes) {
+		t.Errorf("CalibrateH1993 RMSE = %v with %d residuals, want a fit to the %d quotes", fit.RMSE, len(fit.Residuals), len(quotes))
+	}
+}
+
+func TestCalibrateH1993Feller(t *testing.T) {
+	// The parameters that generate the quotes violate the Feller condition,
+	// so the calibration with it cannot fit them exactly.
+	quotes := hestonQuotes(t, HestonParams{V0: 0.04, Kappa: 1.0, Theta: 0.04, Sigma: 0.8, Rho: -0.6}, 100.0, 0.03, 0.01)
+	guess := HestonParams{V0: 0.03, Kappa: 2.0, Theta: 0.05, Sigma: 0.2, Rho: -0.3}
+	cfg := DefaultHestonCalibrationConfig()
+	cfg.Feller = true
+	fit, err := CalibrateH1993(quotes, 100.0, 0.03, 0.01, guess, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := fit.Params
+	if p.Sigma > Sqrt(2.0*p.Kappa*p.Theta)*(1.0+1.0e-12) {
+		t.Errorf("CalibrateH1993 with the Feller condition = %+v, which violates it", p)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"fmt"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidChain is returned when the slices of an option chain and
+the output slice are not all of the same length.
+*/
+type ErrInvalidChain string
+
+func (e ErrInvalidChain) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+================================================================
+Provides the batch pricer for option chains under the
+Generalized Black Scholes Merton pricing model.
+================================================================
+*/
+
+/*
+Chain is the structure that holds the terms of the options of an option
+chain on the same underlying instrument, as slices of equal length with
+one element per option. The options are best grouped by expiry, since the
+discount factors of consecutive options with the same expiry are reused.
+
+Usage (example):
+var c = analytical.Chain{
+	Types:    []options.OptionType{options.Call, options.Put},
+	Strikes:  []float64{95.0, 105.0},
+	Expiries: []float64{0.5, 0.5},
+	Vols:     []float64{0.32, 0.29},
+}
+*/
+type Chain struct {
+	Types    []OptionType
+	Strikes  []float64
+	Expiries []float64
+	Vols     []float64
+}
+
+/*
+--------------------------------------------------------------------------
+GBSMChain -- Generalized Black Scholes Merton pricing model for option
+chains
+
+Description:
+A function that computes the theoretical values and greeks of the options
+of an option chain, and saves the computed results in the elements of the
+preallocated output slice out, with the greeks of each element scaled by
+its market conventions. The results are those of calling the GBSM method
+for each option, with which it shares its kernel, but the discount factors
+are computed once per expiry. It returns the error ErrInvalidChain if the
+slices of the chain and out are not all of the same length, and otherwise
+the first error returned for an option, after pricing all the options;
+otherwise, it returns nil.
+
+Usage:
+out := make([]analytical.ModelOutputs, len(c.Strikes))
+err := analytical.GBSMChain(out, c, s, r, b)
+
+Arguments:
+out output slice (one element per option of the chain)
+c   option chain (the analytical.Chain type)
+s   spot price of the underlying instrument
+r   risk-free rate
+b   cost of carry
+--------------------------------------------------------------------------
+*/
+func GBSMChain(out []ModelOutputs, c Chain, s float64, r float64, b float64) error {
+	n := len(out)
+	if len(c.Types) != n || len(c.Strikes) != n || len(c.Expiries) != n || len(c.Vols) != n {
+		return ErrInvalidChain("The slices of the option chain and the output slice are of different length.")
+	}
+	var first error
+	var t, sqrtT, carry, disc float64
+	for i := range out {
+		// Reuse the discount factors while the expiry does not change.
+		if i == 0 || c.Expiries[i] != t {
+			t = c.Expiries[i]
+			sqrtT = Sqrt(t)
+			carry = Exp((b - r) * t)
+			disc = Exp((-r) * t)
+		}
+		err := out[i].gbsmKernel("GBSMChain", c.Types[i], s, c.Strikes[i], t, c.Vols[i], r, b, sqrtT, carry, disc)
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,103 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "github.com/kervinlow/quantstruct/options"
+	"testing"
+)
+
+/*
+testChain returns an option chain of calls and puts at 100 strikes for each
+of 5 expiries, grouped by expiry, with a volatility smile.
+*/
+func testChain() Chain {
+	var c Chain
+	for _, t := range []float64{0.1, 0.25, 0.5, 1.0, 2.0} {
+		for i := 0; i < 100; i++ {
+			k := 50.0 + float64(i)
+			ot := Call
+			if k < 100.0 {
+				ot = Put
+			}
+			x := (k - 100.0) / 100.0
+			c.Types = append(c.Types, ot)
+			c.Strikes = append(c.Strikes, k)
+			c.Expiries = append(c.Expiries, t)
+			c.Vols = append(c.Vols, 0.2+0.1*x*x)
+		}
+	}
+	return c
+}
+
+func TestGBSMChainAgainstGBSM(t *testing.T) {
+	c := testChain()
+	out := make([]ModelOutputs, len(c.Strikes))
+	if err := GBSMChain(out, c, 100.0, 0.05, 0.02); err != nil {
+		t.Fatal(err)
+	}
+	for i := range out {
+		var want ModelOutputs
+		if err := want.GBSM(c.Types[i], 100.0, c.Strikes[i], c.Expiries[i], c.Vols[i], 0.05, 0.02); err != nil {
+			t.Fatal(err)
+		}
+		if out[i] != want {
+			t.Fatalf("GBSMChain[%d] = %+v, want %+v", i, out[i], want)
+		}
+	}
+}
+
+func TestGBSMChainInvalid(t *testing.T) {
+	c := testChain()
+	if err := GBSMChain(make([]ModelOutputs, 1), c, 100.0, 0.05, 0.02); err == nil {
+		t.Error("GBSMChain with an output slice of a different length returned no error")
+	}
+}
+
+func BenchmarkGBSMChain(b *testing.B) {
+	c := testChain()
+	out := make([]ModelOutputs, len(c.Strikes))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := GBSMChain(out, c, 100.0, 0.05, 0.02); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGBSMLoop(b *testing.B) {
+	c := testChain()
+	out := make([]ModelOutputs, len(c.Strikes))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range out {
+			if err := out[i].GBSM(c.Types[i], 100.0, c.Strikes[i], c.Expiries[i], c.Vols[i], 0.05, 0.02); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
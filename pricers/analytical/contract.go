@@ -0,0 +1,175 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"fmt"
+	"github.com/kervinlow/quantstruct/calendar"
+	"github.com/kervinlow/quantstruct/daycount"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	"time"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrUnsupportedContract is returned when no analytical pricer in
+the package can value an option contract.
+*/
+type ErrUnsupportedContract string
+
+func (e ErrUnsupportedContract) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+=============================================================
+Provides the pricer entry point that values an option contract
+from its terms and the market data of its underlying instrument.
+=============================================================
+*/
+
+/*
+MarketData represents the market data that an option contract is valued
+with. If DayCount is nil, the ACT/365 Fixed convention is used, and if
+Calendar is nil, the dates are not rolled to business days.
+
+Usage (example):
+var md = analytical.MarketData{
+	ValuationDate: time.Date(2016, 6, 30, 0, 0, 0, 0, time.UTC),
+	Spot:          100.0,
+	Volatility:    0.3,
+	Rate:          0.05,
+	Carry:         0.05,
+}
+*/
+type MarketData struct {
+	ValuationDate time.Time
+	Spot          float64
+	Volatility    float64
+	Rate          float64
+	Carry         float64     // ignored if there are dividends
+	Dividends     DivSchedule // discrete dividends of the underlying instrument
+	DayCount      daycount.Convention
+	Calendar      *calendar.Calendar
+	Convention    calendar.BusinessDayConvention
+}
+
+/*
+--------------------------------------------------------------------------
+Price -- Option contract pricer
+
+Description:
+A method that computes the theoretical value and greeks of an option
+contract from its terms and the given market data, and saves the computed
+results, per contract (i.e. multiplied by the contract multiplier), in the
+fields of the ModelOutputs receiver; Lambda, being a ratio, is left per
+unit. A European option is valued with the BV2002Dated method, in the
+currency of the contract, if the market data has discrete dividends, or
+with the GBSMDated method otherwise, and an American option without
+discrete dividends is valued with the BJS2002 method, or with the GBSM
+method on its expiry date. The settlement of the contract does not affect
+its value under these pricing models. It returns the error returned by
+the options.Option.Validate method if the terms of the contract are
+invalid, the error ErrUnsupportedContract if the contract is a Bermudan
+option or an American option with discrete dividends, or the error
+returned by the pricing method if it fails; otherwise, it returns nil.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.Price(o, md)
+
+Arguments:
+o  option contract (the options.Option type in the options
+   package)
+md market data (the analytical.MarketData type)
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) Price(o Option, md MarketData) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	dc := md.DayCount
+	if dc == nil {
+		dc = daycount.Actual365Fixed{}
+	}
+	var err error
+	switch {
+	case o.Exercise == European && len(md.Dividends) > 0:
+		err = out.BV2002Dated(o.Type, md.Spot, o.Strike, md.ValuationDate, o.Expiry, dc, md.Calendar, md.Convention, md.Volatility, md.Rate, md.Dividends, o.Currency)
+	case o.Exercise == European:
+		err = out.GBSMDated(o.Type, md.Spot, o.Strike, md.ValuationDate, o.Expiry, dc, md.Calendar, md.Convention, md.Volatility, md.Rate, md.Carry)
+	case o.Exercise == American && len(md.Dividends) == 0:
+		ed := o.Expiry
+		if md.Calendar != nil {
+			ed = md.Calendar.Adjust(ed, md.Convention)
+		}
+		// An American option on its expiry date is worth the same as a European one.
+		if t := dc.YearFraction(md.ValuationDate, ed); t == 0.0 {
+			err = out.GBSM(o.Type, md.Spot, o.Strike, t, md.Volatility, md.Rate, md.Carry)
+		} else {
+			err = out.BJS2002(o.Type, md.Spot, o.Strike, t, md.Volatility, md.Rate, md.Carry)
+		}
+	default:
+		return ErrUnsupportedContract("No analytical pricer can value the option contract.")
+	}
+	if err != nil {
+		return err
+	}
+	out.multiply(o.Multiplier)
+	return nil
+}
+
+/*
+multiply is an unexported method that multiplies the value and greeks in
+the fields of the ModelOutputs receiver by the factor f, except for
+Lambda, which is a ratio.
+*/
+func (out *ModelOutputs) multiply(f float64) {
+	out.Value *= f
+	out.Delta *= f
+	out.Gamma *= f
+	out.Vega *= f
+	out.Theta *= f
+	out.Rho *= f
+	out.Vanna *= f
+	out.Volga *= f
+	out.Charm *= f
+	out.Speed *= f
+	out.Zomma *= f
+	out.Color *= f
+	out.Veta *= f
+	out.Ultima *= f
+	out.DualDelta *= f
+	out.DualGamma *= f
+	out.CarryRho *= f
+}
@@ -0,0 +1,164 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"github.com/kervinlow/quantstruct/daycount"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+	"testing"
+	"time"
+)
+
+func contractData() (Option, MarketData) {
+	o := Option{
+		Underlying: "XYZ",
+		Type:       Put,
+		Strike:     100.0,
+		Expiry:     time.Date(2016, 12, 30, 0, 0, 0, 0, time.UTC),
+		Exercise:   European,
+		Settlement: CashSettled,
+		Multiplier: 1.0,
+		Currency:   "USD",
+	}
+	md := MarketData{
+		ValuationDate: time.Date(2016, 6, 30, 0, 0, 0, 0, time.UTC),
+		Spot:          95.0,
+		Volatility:    0.3,
+		Rate:          0.05,
+		Carry:         0.02,
+	}
+	return o, md
+}
+
+func TestPriceMatchesPricers(t *testing.T) {
+	o, md := contractData()
+	tm := daycount.Actual365Fixed{}.YearFraction(md.ValuationDate, o.Expiry)
+	var got, want ModelOutputs
+	if err := got.Price(o, md); err != nil {
+		t.Fatal(err)
+	}
+	if err := want.GBSM(o.Type, md.Spot, o.Strike, tm, md.Volatility, md.Rate, md.Carry); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Price of a European option = %+v, want the GBSM outputs %+v", got, want)
+	}
+	o.Exercise = American
+	if err := got.Price(o, md); err != nil {
+		t.Fatal(err)
+	}
+	if err := want.BJS2002(o.Type, md.Spot, o.Strike, tm, md.Volatility, md.Rate, md.Carry); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Price of an American option = %+v, want the BJS2002 outputs %+v", got, want)
+	}
+	md.ValuationDate = o.Expiry
+	if err := got.Price(o, md); err != nil {
+		t.Fatal(err)
+	}
+	if err := want.GBSM(o.Type, md.Spot, o.Strike, 0.0, md.Volatility, md.Rate, md.Carry); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Price of an American option on its expiry date = %+v, want the GBSM outputs %+v", got, want)
+	}
+}
+
+func TestPriceMultiplier(t *testing.T) {
+	o, md := contractData()
+	for _, ex := range []ExerciseStyle{European, American} {
+		o.Exercise = ex
+		var unit, lot ModelOutputs
+		o.Multiplier = 1.0
+		if err := unit.Price(o, md); err != nil {
+			t.Fatal(err)
+		}
+		o.Multiplier = 100.0
+		if err := lot.Price(o, md); err != nil {
+			t.Fatal(err)
+		}
+		pairs := []struct {
+			name      string
+			unit, lot float64
+		}{
+			{"Value", unit.Value, lot.Value},
+			{"Delta", unit.Delta, lot.Delta},
+			{"Gamma", unit.Gamma, lot.Gamma},
+			{"Vega", unit.Vega, lot.Vega},
+			{"Theta", unit.Theta, lot.Theta},
+			{"Rho", unit.Rho, lot.Rho},
+		}
+		for _, p := range pairs {
+			if Abs(p.lot-100.0*p.unit) > 1e-9*Abs(p.lot) {
+				t.Errorf("exercise %v: %s per contract = %v, want 100 * %v", ex, p.name, p.lot, p.unit)
+			}
+		}
+	}
+}
+
+func TestPriceErrors(t *testing.T) {
+	ds := DivSchedule{{
+		ExDate:   time.Date(2016, 9, 1, 0, 0, 0, 0, time.UTC),
+		PayDate:  time.Date(2016, 9, 15, 0, 0, 0, 0, time.UTC),
+		Amount:   2.0,
+		Currency: "USD",
+		Kind:     CashDividend,
+	}}
+	var out ModelOutputs
+
+	o, md := contractData()
+	o.Strike = -100.0
+	if _, ok := out.Price(o, md).(ErrInvalidOption); !ok {
+		t.Error("Price of an option with a negative strike did not return ErrInvalidOption")
+	}
+
+	o, md = contractData()
+	o.Exercise = Bermudan
+	o.ExerciseDates = []time.Time{o.Expiry.AddDate(0, -3, 0), o.Expiry}
+	if _, ok := out.Price(o, md).(ErrUnsupportedContract); !ok {
+		t.Error("Price of a Bermudan option did not return ErrUnsupportedContract")
+	}
+
+	o, md = contractData()
+	o.Exercise = American
+	md.Dividends = ds
+	if _, ok := out.Price(o, md).(ErrUnsupportedContract); !ok {
+		t.Error("Price of an American option with dividends did not return ErrUnsupportedContract")
+	}
+
+	o.Exercise = European
+	if err := out.Price(o, md); err != nil {
+		t.Errorf("Price of a European option with dividends returned %v", err)
+	}
+	o.Currency = "EUR"
+	if _, ok := out.Price(o, md).(ErrCurrencyMismatch); !ok {
+		t.Error("Price with dividends in another currency did not return ErrCurrencyMismatch")
+	}
+}
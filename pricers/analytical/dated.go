@@ -0,0 +1,155 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"github.com/kervinlow/quantstruct/calendar"
+	"github.com/kervinlow/quantstruct/daycount"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	"time"
+)
+
+/*
+=============================================================
+Provides the date-based entry points to the analytical pricers,
+which roll the dates to business days with a holiday calendar and
+compute the time arguments from them with a day-count convention.
+=============================================================
+*/
+
+/*
+--------------------------------------------------------------------------
+GBSMDated -- Generalized Black Scholes Merton pricing model on dates
+
+Description:
+A method that computes the theoretical value and greeks of a financial
+option as the GBSM method does, with the time to expiry computed as the
+year fraction from the valuation date to the expiry date under the given
+day-count convention, and saves the computed results in the fields of the
+ModelOutputs receiver. The expiry date is first rolled to a business day
+of the given calendar with the given business-day convention, unless the
+calendar is nil. It returns the same errors as GBSM.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.GBSMDated(ot, s, k, vd, ed, dc, cal, bdc, v, r, b)
+
+Arguments:
+ot  option type (either options.Call or options.Put from
+    the options package)
+s   spot price of the underlying instrument
+k   strike price of the option
+vd  valuation date
+ed  expiry date of the option
+dc  day-count convention (any type that implements the
+    daycount.Convention interface in the daycount package)
+cal holiday calendar (the *calendar.Calendar type in the
+    calendar package), or nil
+bdc business-day convention (e.g. calendar.ModifiedFollowing
+    from the calendar package)
+v   volatility of the underlying instrument
+r   risk-free rate
+b   cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) GBSMDated(ot OptionType, s float64, k float64, vd time.Time, ed time.Time, dc daycount.Convention, cal *calendar.Calendar, bdc calendar.BusinessDayConvention, v float64, r float64, b float64) error {
+	if cal != nil {
+		ed = cal.Adjust(ed, bdc)
+	}
+	err := out.GBSM(ot, s, k, dc.YearFraction(vd, ed), v, r, b)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+/*
+--------------------------------------------------------------------
+BV2002Dated -- Bos and Vandermark (2002) pricing model on dates
+
+Description:
+A method that computes the theoretical value and greeks of an option
+on a stock that pays discrete dividends as the BV2002 method does,
+with the time to expiry and the times to the ex-dates of the
+dividends computed as year fractions from the valuation date under
+the given day-count convention, and saves the computed results in
+the fields of the ModelOutputs receiver. The expiry date and the ex-
+and pay-dates of the dividends are first rolled to business days of
+the given calendar with the given business-day convention, unless the
+calendar is nil. Dividends that go ex on or before the valuation date
+are ignored, and yield dividends are converted to cash at the spot
+price. It returns the error returned by the
+equity.DivSchedule.ToDivList method if the dividend schedule is
+invalid or a dividend is paid in a currency other than ccy, and
+otherwise the same errors as BV2002Curves.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.BV2002Dated(ot, s, k, vd, ed, dc, cal, bdc, v, r, ds, ccy)
+
+Arguments:
+ot  option type (either options.Call or options.Put from
+    the options package)
+s   spot price of the underlying instrument
+k   strike price of the option
+vd  valuation date
+ed  expiry date of the option
+dc  day-count convention (any type that implements the
+    daycount.Convention interface in the daycount package)
+cal holiday calendar (the *calendar.Calendar type in the
+    calendar package), or nil
+bdc business-day convention (e.g. calendar.ModifiedFollowing
+    from the calendar package)
+v   volatility of the underlying instrument
+r   risk-free rate
+ds  dated dividend schedule (the equity.DivSchedule type
+    in the equity package)
+ccy currency of the option (e.g. "USD"), or "" to accept
+    dividends in any currency
+--------------------------------------------------------------------
+*/
+func (out *ModelOutputs) BV2002Dated(ot OptionType, s float64, k float64, vd time.Time, ed time.Time, dc daycount.Convention, cal *calendar.Calendar, bdc calendar.BusinessDayConvention, v float64, r float64, ds DivSchedule, ccy string) error {
+	if cal != nil {
+		ed = cal.Adjust(ed, bdc)
+		adj := make(DivSchedule, len(ds))
+		for i, d := range ds {
+			d.ExDate, d.PayDate = cal.Adjust(d.ExDate, bdc), cal.Adjust(d.PayDate, bdc)
+			adj[i] = d
+		}
+		ds = adj
+	}
+	dl, err := ds.ToDivList(vd, s, ccy, dc.YearFraction)
+	if err != nil {
+		return err
+	}
+	err = out.BV2002(ot, s, k, dc.YearFraction(vd, ed), v, r, dl)
+	if err != nil {
+		return err
+	}
+	return nil
+}
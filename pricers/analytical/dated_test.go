@@ -0,0 +1,59 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"github.com/kervinlow/quantstruct/calendar"
+	"github.com/kervinlow/quantstruct/daycount"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	"testing"
+	"time"
+)
+
+func TestBV2002DatedCurrency(t *testing.T) {
+	vd := time.Date(2016, 6, 30, 0, 0, 0, 0, time.UTC)
+	ed := time.Date(2016, 12, 30, 0, 0, 0, 0, time.UTC)
+	ds := DivSchedule{{
+		ExDate:   time.Date(2016, 9, 1, 0, 0, 0, 0, time.UTC),
+		PayDate:  time.Date(2016, 9, 15, 0, 0, 0, 0, time.UTC),
+		Amount:   2.0,
+		Currency: "USD",
+		Kind:     CashDividend,
+	}}
+	var out ModelOutputs
+	if err := out.BV2002Dated(Call, 100.0, 100.0, vd, ed, daycount.Actual365Fixed{}, nil, calendar.Unadjusted, 0.3, 0.05, ds, "USD"); err != nil {
+		t.Errorf("BV2002Dated in the currency of the dividends returned %v", err)
+	}
+	if err := out.BV2002Dated(Call, 100.0, 100.0, vd, ed, daycount.Actual365Fixed{}, nil, calendar.Unadjusted, 0.3, 0.05, ds, ""); err != nil {
+		t.Errorf("BV2002Dated in no currency returned %v", err)
+	}
+	err := out.BV2002Dated(Call, 100.0, 100.0, vd, ed, daycount.Actual365Fixed{}, nil, calendar.Unadjusted, 0.3, 0.05, ds, "EUR")
+	if _, ok := err.(ErrCurrencyMismatch); !ok {
+		t.Errorf("BV2002Dated in another currency returned %v, want ErrCurrencyMismatch", err)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The sentinel errors below are the categories of the errors returned by the
+pricing methods, and can be tested for with errors.Is.
+*/
+var (
+	ErrInvalidInput = errors.New("The inputs of the pricer are invalid.")
+	ErrNonFinite    = errors.New("The pricer has computed a value that is not a finite number.")
+	ErrArbitrage    = errors.New("The inputs of the pricer violate a no-arbitrage condition.")
+)
+
+/*
+InputError is the error returned when an argument of a pricing method is
+invalid; it is in the category ErrInvalidInput. Inputs holds all the
+numerical arguments of the pricing method by name; if the invalid argument
+is not numerical (e.g. a nil curve), it is not in Inputs and Value is NaN.
+*/
+type InputError struct {
+	Pricer string // name of the pricing method
+	Field  string // name of the invalid argument
+	Value  float64
+	Reason string // what the argument must be
+	Inputs map[string]float64
+}
+
+func (e *InputError) Error() string {
+	if _, ok := e.Inputs[e.Field]; !ok {
+		return fmt.Sprintf("%s: The argument %s %s.", e.Pricer, e.Field, e.Reason)
+	}
+	return fmt.Sprintf("%s: The argument %s %s, but is %v.", e.Pricer, e.Field, e.Reason, e.Value)
+}
+
+func (e *InputError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+/*
+OutputError is the error returned when the value or a greek computed by a
+pricing method is not a finite number; it is in the category ErrNonFinite.
+Inputs holds all the numerical arguments of the pricing method by name.
+*/
+type OutputError struct {
+	Pricer string // name of the pricing method
+	Field  string // name of the field of ModelOutputs
+	Value  float64
+	Inputs map[string]float64
+}
+
+func (e *OutputError) Error() string {
+	return fmt.Sprintf("%s: The %s is not a finite number, but is %v.", e.Pricer, e.Field, e.Value)
+}
+
+func (e *OutputError) Is(target error) bool {
+	return target == ErrNonFinite
+}
+
+/*
+ArbitrageError is the error returned when the arguments of a pricing method
+violate a no-arbitrage condition; it is in the category ErrArbitrage.
+Inputs holds all the numerical arguments of the pricing method by name.
+*/
+type ArbitrageError struct {
+	Pricer string // name of the pricing method
+	Reason string // the condition that is violated
+	Inputs map[string]float64
+}
+
+func (e *ArbitrageError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pricer, e.Reason)
+}
+
+func (e *ArbitrageError) Is(target error) bool {
+	return target == ErrArbitrage
+}
+
+/*
+================
+Input Validation
+================
+*/
+
+/*
+field is the name and the value of an argument of a pricing method, or of
+a field of ModelOutputs.
+*/
+type field struct {
+	name  string
+	value float64
+}
+
+/*
+inputMap is an unexported function that returns the arguments of a pricing
+method by name.
+*/
+func inputMap(in []field) map[string]float64 {
+	m := make(map[string]float64, len(in))
+	for _, f := range in {
+		m[f.name] = f.value
+	}
+	return m
+}
+
+/*
+checkInputs is an unexported function that returns an *InputError for the
+first of the arguments of a pricing method that is not a finite number, or
+that is not positive if its name is one of positive; otherwise, it returns
+nil.
+*/
+func checkInputs(pricer string, in []field, positive ...string) error {
+	for _, f := range in {
+		if IsNaN(f.value) || IsInf(f.value, 0) {
+			return &InputError{pricer, f.name, f.value, "must be a finite number", inputMap(in)}
+		}
+		for _, name := range positive {
+			if f.name == name && f.value <= 0.0 {
+				return &InputError{pricer, f.name, f.value, "must be positive", inputMap(in)}
+			}
+		}
+	}
+	return nil
+}
+
+/*
+checkOptionType is an unexported function that returns an *InputError if
+the vanilla payoff of the option type is invalid, i.e. the option type is
+neither Call nor Put; otherwise, it returns nil.
+*/
+func checkOptionType(pricer string, ot OptionType, in []field) error {
+	if err := (Vanilla{Type: ot}).Validate(); err != nil {
+		return &InputError{pricer, "ot", float64(ot), "must be options.Call or options.Put", inputMap(in)}
+	}
+	return nil
+}
+
+/*
+checkBarrierType is an unexported function that returns an *InputError if
+the barrier type is not one of the BarrierType values; otherwise, it
+returns nil.
+*/
+func checkBarrierType(pricer string, bt BarrierType, in []field) error {
+	if bt != DownAndIn && bt != UpAndIn && bt != DownAndOut && bt != UpAndOut {
+		return &InputError{pricer, "bt", float64(bt), "must be options.DownAndIn, options.UpAndIn, options.DownAndOut or options.UpAndOut", inputMap(in)}
+	}
+	return nil
+}
+
+/*
+checkNonNegative is an unexported function that returns an *InputError for
+the first of the named arguments of a pricing method that is negative;
+otherwise, it returns nil.
+*/
+func checkNonNegative(pricer string, in []field, names ...string) error {
+	for _, f := range in {
+		for _, name := range names {
+			if f.name == name && f.value < 0.0 {
+				return &InputError{pricer, f.name, f.value, "must not be negative", inputMap(in)}
+			}
+		}
+	}
+	return nil
+}
+
+/*
+checkGBSMInputs is an unexported function that returns the arguments of a
+pricing method that takes the same arguments as GBSM, and an *InputError if
+the option type is neither Call nor Put, if s or k is not positive, if t
+or v is negative, or if any argument is not a finite number.
+*/
+func checkGBSMInputs(pricer string, ot OptionType, s float64, k float64, t float64, v float64, r float64, b float64) ([]field, error) {
+	in := []field{{"ot", float64(ot)}, {"s", s}, {"k", k}, {"t", t}, {"v", v}, {"r", r}, {"b", b}}
+	if err := checkOptionType(pricer, ot, in); err != nil {
+		return in, err
+	}
+	if err := checkInputs(pricer, in, "s", "k"); err != nil {
+		return in, err
+	}
+	return in, checkNonNegative(pricer, in, "t", "v")
+}
@@ -0,0 +1,192 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "github.com/kervinlow/quantstruct/math"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+	"math/cmplx"
+)
+
+/*
+==================================================================
+Provides the semi-analytical Heston (1993) stochastic volatility
+pricing model.
+==================================================================
+*/
+
+/*
+Number of points of the Gauss-Laguerre rule used to integrate the Heston
+characteristic function, and its abscissae and weights.
+*/
+const hestonNodes = 128
+
+var hestonX, hestonW = GaussLaguerre(hestonNodes)
+
+/*
+--------------------------------------------------------------------------
+H1993 -- Heston (1993) pricing model
+
+Description:
+A method that computes the theoretical value and greeks of a European
+option whose underlying instrument has the stochastic variance of Heston,
+which starts at v0 and reverts at the rate kappa to the long-run variance
+theta with the volatility of variance sigma, and is correlated with the
+underlying price by rho. It saves the computed results in the fields of
+the ModelOutputs receiver. The underlying instrument has the cost of
+carry b, as in GBSM. The value is found by integrating the characteristic
+function of the log price, in the "little trap" formulation of Albrecher
+et al. (2007) that stays on the principal branch of the complex
+logarithm, with a Gauss-Laguerre rule, and with the Black-Scholes value
+at the expected average variance to expiry as a control variate. The
+greeks are computed numerically, and are scaled by the same market
+conventions as GBSM; Vega is the sensitivity to the initial volatility
+Sqrt(v0), and all the greeks hold the other Heston parameters fixed. It
+returns an *InputError if the option type is neither Call nor Put, if s,
+k, t, kappa or sigma is not positive, if v0 or theta is negative, if rho
+is not between -1 and 1, or if any argument is not a finite number, or an
+*OutputError if the value or a greek is not a finite number; otherwise,
+it returns nil.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.H1993(ot, s, k, t, v0, r, b, kappa, theta, sigma, rho)
+
+Arguments:
+ot    option type (either options.Call or options.Put from
+      the options package)
+s     spot price of the underlying instrument
+k     strike price of the option
+t     time to expiry of the option
+v0    initial variance of the underlying instrument
+r     risk-free rate
+b     cost of carry
+kappa rate of mean reversion of the variance
+theta long-run variance
+sigma volatility of the variance
+rho   correlation between the underlying price and its variance
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) H1993(ot OptionType, s float64, k float64, t float64, v0 float64, r float64, b float64, kappa float64, theta float64, sigma float64, rho float64) error {
+	// Validate the inputs before computing anything.
+	in := []field{{"ot", float64(ot)}, {"s", s}, {"k", k}, {"t", t}, {"v0", v0}, {"r", r}, {"b", b}, {"kappa", kappa}, {"theta", theta}, {"sigma", sigma}, {"rho", rho}}
+	if err := checkOptionType("H1993", ot, in); err != nil {
+		return err
+	}
+	if err := checkInputs("H1993", in, "s", "k", "t", "kappa", "sigma"); err != nil {
+		return err
+	}
+	if err := checkNonNegative("H1993", in, "v0", "theta"); err != nil {
+		return err
+	}
+	if rho < -1.0 || rho > 1.0 {
+		return &InputError{"H1993", "rho", rho, "must be between -1 and 1", inputMap(in)}
+	}
+	// The greeks are bumped on the initial volatility, not the variance.
+	price := func(s, t, v, r, b float64) float64 {
+		return getH1993Value(ot, s, k, t, v*v, r, b, kappa, theta, sigma, rho)
+	}
+	return out.numericalGreeks("H1993", price, s, t, Sqrt(v0), r, b)
+}
+
+/*
+getH1993Value is an unexported function that computes the theoretical
+value of a European option using the Heston (1993) pricing model. The call
+is valued as the Black-Scholes call at the volatility Sqrt(w), where w is
+the expected average variance to expiry, plus the correction
+
+Exp(-r*t)/Pi * Integral of Re[Exp(i*u*x) * (F*q(u - i) - k*q(u)) / (i*u)]
+du from 0 to infinity
+
+where F is the forward price, x = Log(F/k), and q is the difference
+between the Heston and Black-Scholes characteristic functions of
+Log(S(t)/F). The difference decays faster than either function, which
+keeps the quadrature accurate for short expiries and deep out-of-the-money
+options. The put is valued by put-call parity.
+*/
+func getH1993Value(ot OptionType, s float64, k float64, t float64, v0 float64, r float64, b float64, kappa float64, theta float64, sigma float64, rho float64) float64 {
+	f, disc := s*Exp(b*t), Exp((-r)*t)
+	x := Log(f / k)
+	w := theta + ((v0 - theta) * (1.0 - Exp((-kappa)*t)) / (kappa * t))
+	var call float64
+	if w*t == 0.0 {
+		// The variance starts at zero and reverts to zero, so it stays there.
+		call = disc * Max(f-k, 0.0)
+	} else {
+		sd := Sqrt(w * t)
+		d1 := (x / sd) + (0.5 * sd)
+		call = disc * ((f * CDF(d1)) - (k * CDF(d1-sd)))
+		var sum float64
+		for i, u := range hestonX {
+			z, iu := complex(u, 0.0), complex(0.0, u)
+			q1 := getH1993CF(z-1i, t, v0, kappa, theta, sigma, rho) - cmplx.Exp(complex(-0.5*w*t, 0.0)*((iu+1.0)+(z-1i)*(z-1i)))
+			q0 := getH1993CF(z, t, v0, kappa, theta, sigma, rho) - cmplx.Exp(complex(-0.5*w*t, 0.0)*(iu+z*z))
+			integrand := cmplx.Exp(iu*complex(x, 0.0)) * (complex(f, 0.0)*q1 - complex(k, 0.0)*q0) / iu
+			sum += hestonW[i] * Exp(u) * real(integrand)
+		}
+		call += disc * sum / Pi
+	}
+	if ot == Put {
+		return call - (disc * (f - k))
+	}
+	return call
+}
+
+/*
+getH1993CF is an unexported function that computes the characteristic
+function of Log(S(t)/F) at u under the Heston (1993) pricing model, in the
+"little trap" formulation. The differences beta - d and Log((1 - g*e)/(1 -
+g)) are computed without cancellation, which keeps the function accurate
+as sigma tends to zero.
+*/
+func getH1993CF(u complex128, t float64, v0 float64, kappa float64, theta float64, sigma float64, rho float64) complex128 {
+	iu := complex(0.0, 1.0) * u
+	q := iu + u*u
+	beta := complex(kappa, 0.0) - complex(rho*sigma, 0.0)*iu
+	d := cmplx.Sqrt(beta*beta + complex(sigma*sigma, 0.0)*q)
+	// beta - d = -sigma^2 * q / (beta + d), and g = (beta - d)/(beta + d).
+	a := -q / (beta + d)
+	g := complex(sigma*sigma, 0.0) * a / (beta + d)
+	e := cmplx.Exp(-d * complex(t, 0.0))
+	l := getLog1p(g * (1.0 - e) / (1.0 - g))
+	c := complex(kappa*theta, 0.0) * (a*complex(t, 0.0) - complex(2.0/(sigma*sigma), 0.0)*l)
+	dv := a * (1.0 - e) / (1.0 - g*e)
+	return cmplx.Exp(c + dv*complex(v0, 0.0))
+}
+
+/*
+getLog1p is an unexported function that computes Log(1 + z) for a complex
+z, accurately also when z is small.
+*/
+func getLog1p(z complex128) complex128 {
+	w := 1.0 + z
+	if w == 1.0 {
+		return z
+	}
+	return cmplx.Log(w) * z / (w - 1.0)
+}
@@ -0,0 +1,67 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+	"testing"
+)
+
+func TestH1993FangOosterlee(t *testing.T) {
+	// The call of Fang and Oosterlee (2008), whose reference value is
+	// 5.785155450.
+	var out ModelOutputs
+	if err := out.H1993(Call, 100.0, 100.0, 1.0, 0.0175, 0.0, 0.0, 1.5768, 0.0398, 0.5751, -0.5711); err != nil {
+		t.Fatal(err)
+	}
+	if Abs(out.Value-5.785155450) > 1.0e-7 {
+		t.Errorf("H1993 = %.9f, want 5.785155450", out.Value)
+	}
+}
+
+func TestH1993ZeroVolOfVol(t *testing.T) {
+	// As sigma tends to zero, the variance follows its expected path, and the
+	// option has the GBSM value at the expected average variance to expiry;
+	// the difference is of the order of sigma.
+	const s, T, v0, r, b, kappa, theta = 100.0, 0.75, 0.09, 0.05, 0.02, 2.0, 0.04
+	w := theta + (v0-theta)*(1.0-Exp(-kappa*T))/(kappa*T)
+	for _, ot := range []OptionType{Call, Put} {
+		for _, k := range []float64{80.0, 100.0, 120.0} {
+			var want, out ModelOutputs
+			if err := want.GBSM(ot, s, k, T, Sqrt(w), r, b); err != nil {
+				t.Fatal(err)
+			}
+			if err := out.H1993(ot, s, k, T, v0, r, b, kappa, theta, 1.0e-10, -0.5); err != nil {
+				t.Fatal(err)
+			}
+			if Abs(out.Value-want.Value) > 1.0e-9 {
+				t.Errorf("H1993(%v, k=%v, sigma=1e-10) = %.10f, want GBSM = %.10f", ot, k, out.Value, want.Value)
+			}
+		}
+	}
+}
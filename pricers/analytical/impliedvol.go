@@ -0,0 +1,438 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"fmt"
+	"github.com/kervinlow/quantstruct/curves"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrBelowIntrinsic is returned when the target price of an option
+is below its intrinsic value, i.e. below the value of the option at zero
+volatility. It is in the category ErrArbitrage.
+*/
+type ErrBelowIntrinsic string
+
+func (e ErrBelowIntrinsic) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+func (e ErrBelowIntrinsic) Is(target error) bool {
+	return target == ErrArbitrage
+}
+
+/*
+The error ErrAboveUpperBound is returned when the target price of an option
+is at or above its no-arbitrage upper bound, i.e. the value of the option at
+infinite volatility. It is in the category ErrArbitrage.
+*/
+type ErrAboveUpperBound string
+
+func (e ErrAboveUpperBound) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+func (e ErrAboveUpperBound) Is(target error) bool {
+	return target == ErrArbitrage
+}
+
+/*
+The error ErrNoConvergence is returned when an iterative solver fails to
+converge within its maximum number of iterations.
+*/
+type ErrNoConvergence string
+
+func (e ErrNoConvergence) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+================================================================
+Provides the implied volatility solvers for the Black-Scholes-Merton
+family of pricing models.
+================================================================
+*/
+
+const (
+	ivTolerance     = 1.0e-12 // price tolerance, relative to the target price
+	ivVolTolerance  = 1.0e-12 // width of the bracket, relative to the volatility
+	ivMaxIterations = 100     // maximum number of Newton/bisection iterations
+	ivMaxBracket    = 30      // maximum number of doublings of the upper vol
+)
+
+/*
+--------------------------------------------------------------------------
+GBSMImpliedVol -- Implied volatility of the Generalized Black Scholes
+Merton pricing model
+
+Description:
+A function that returns the volatility at which the GBSM method reprices
+a financial option to the target price p. It uses Newton's method on the
+Vega computed by GBSM, safeguarded by bisection on a bracketing interval.
+It returns an *InputError if the option type is neither Call nor Put, if
+s, k or t is not positive, or if any argument is not a finite number, the
+error ErrBelowIntrinsic if p is below the option's value at
+zero volatility, the error ErrAboveUpperBound if p is at or above the
+option's value at infinite volatility, and the error ErrNoConvergence if
+the solver fails to converge; otherwise, it returns nil.
+
+Usage:
+vol, err := analytical.GBSMImpliedVol(ot, p, s, k, t, r, b)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+p  target price of the option
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+r  risk-free rate
+b  cost of carry
+--------------------------------------------------------------------------
+*/
+func GBSMImpliedVol(ot OptionType, p float64, s float64, k float64, t float64, r float64, b float64) (float64, error) {
+	if err := checkImpliedVolInputs(ot, p, s, k, t, r, b); err != nil {
+		return NaN(), err
+	}
+	// The option's value at zero and at infinite volatility bound the target price.
+	fwdS, fwdK := s*Exp((b-r)*t), k*Exp((-r)*t)
+	var lower, upper float64
+	switch ot {
+	case Call:
+		lower, upper = Max(fwdS-fwdK, 0.0), fwdS
+	case Put:
+		lower, upper = Max(fwdK-fwdS, 0.0), fwdK
+	}
+	price := func(v float64) (float64, float64, error) {
+		var out ModelOutputs
+		if err := out.GBSM(ot, s, k, t, v, r, b); err != nil {
+			return 0.0, 0.0, err
+		}
+		// Undo the market scaling of Vega so that it is per unit of volatility.
+		return out.Value, out.Raw().Vega, nil
+	}
+	// Start the iteration from the Manaster-Koehler guess.
+	guess := Sqrt(2.0 * Abs(Log(s/k)+b*t) / t)
+	return impliedVol(p, lower, upper, guess, price)
+}
+
+/*
+checkImpliedVolInputs is an unexported function that returns an
+*InputError if the arguments of GBSMImpliedVol are invalid; otherwise, it
+returns nil.
+*/
+func checkImpliedVolInputs(ot OptionType, p float64, s float64, k float64, t float64, r float64, b float64) error {
+	in := []field{{"ot", float64(ot)}, {"p", p}, {"s", s}, {"k", k}, {"t", t}, {"r", r}, {"b", b}}
+	if err := checkOptionType("GBSMImpliedVol", ot, in); err != nil {
+		return err
+	}
+	return checkInputs("GBSMImpliedVol", in, "s", "k", "t")
+}
+
+/*
+impliedVol is an unexported function that solves price(v) = p for the
+volatility v, given the value of the option at zero volatility (lower) and
+at infinite volatility (upper), an initial guess, and a function returning
+the option's value and its unscaled Vega at v. It stops when the value is
+within ivTolerance of p, relative to p, or when the bracket around the
+volatility has shrunk to within ivVolTolerance of it.
+*/
+func impliedVol(p float64, lower float64, upper float64, guess float64, price func(float64) (float64, float64, error)) (float64, error) {
+	if p < lower {
+		return NaN(), ErrBelowIntrinsic("Price is below the intrinsic value.")
+	}
+	if p >= upper {
+		return NaN(), ErrAboveUpperBound("Price is at or above the upper bound.")
+	}
+	if p == lower {
+		return 0.0, nil
+	}
+	// Find an upper volatility whose value exceeds the target price.
+	lo, hi := 0.0, 1.0
+	for n := 0; ; n++ {
+		value, _, err := price(hi)
+		if err != nil {
+			return NaN(), err
+		}
+		if value >= p {
+			break
+		}
+		if n == ivMaxBracket {
+			return NaN(), ErrNoConvergence("Implied volatility could not be bracketed.")
+		}
+		lo, hi = hi, hi*2.0
+	}
+	v := guess
+	if IsNaN(v) || v <= lo || v >= hi {
+		v = (lo + hi) / 2.0
+	}
+	for n := 0; n < ivMaxIterations; n++ {
+		value, vega, err := price(v)
+		if err != nil {
+			return NaN(), err
+		}
+		diff := value - p
+		if Abs(diff) <= ivTolerance*p {
+			return v, nil
+		}
+		// Shrink the bracket, then take a Newton step if it stays inside the bracket.
+		if diff > 0.0 {
+			hi = v
+		} else {
+			lo = v
+		}
+		if hi-lo <= ivVolTolerance*v {
+			return v, nil
+		}
+		next := v - diff/vega
+		if vega <= 0.0 || IsNaN(next) || next <= lo || next >= hi {
+			next = (lo + hi) / 2.0
+		}
+		if next == v {
+			return v, nil
+		}
+		v = next
+	}
+	return NaN(), ErrNoConvergence("Implied volatility did not converge.")
+}
+
+/*
+----------------------------------------------------------------------
+BS1973ImpliedVol -- Implied volatility of the Black and Scholes (1973)
+pricing model
+
+Description:
+A function that returns the volatility at which the BS1973 method
+reprices an option on a stock that does not pay dividend to the target
+price p. It returns the same errors as GBSMImpliedVol.
+
+Usage:
+vol, err := analytical.BS1973ImpliedVol(ot, p, s, k, t, r)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+p  target price of the option
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+r  risk-free rate
+----------------------------------------------------------------------
+*/
+func BS1973ImpliedVol(ot OptionType, p float64, s float64, k float64, t float64, r float64) (float64, error) {
+	return GBSMImpliedVol(ot, p, s, k, t, r, r)
+}
+
+/*
+-------------------------------------------------------------------------
+M1973ImpliedVol -- Implied volatility of the Merton (1973) pricing model
+
+Description:
+A function that returns the volatility at which the M1973 method reprices
+an option on a stock (or stock index) that pays a known continuous
+dividend yield to the target price p. It returns the same errors as
+GBSMImpliedVol.
+
+Usage:
+vol, err := analytical.M1973ImpliedVol(ot, p, s, k, t, r, q)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+p  target price of the option
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+r  risk-free rate
+q  continuous dividend yield
+-------------------------------------------------------------------------
+*/
+func M1973ImpliedVol(ot OptionType, p float64, s float64, k float64, t float64, r float64, q float64) (float64, error) {
+	return GBSMImpliedVol(ot, p, s, k, t, r, r-q)
+}
+
+/*
+--------------------------------------------------------------------------
+B1976ImpliedVol -- Implied volatility of the Black (1976) pricing model
+
+Description:
+A function that returns the volatility at which the B1976 method reprices
+an option on a forward or futures contract to the target price p. It
+returns the same errors as GBSMImpliedVol.
+
+Usage:
+vol, err := analytical.B1976ImpliedVol(ot, p, f, k, t, r)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+p  target price of the option
+f  forward price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+r  risk-free rate
+--------------------------------------------------------------------------
+*/
+func B1976ImpliedVol(ot OptionType, p float64, f float64, k float64, t float64, r float64) (float64, error) {
+	return GBSMImpliedVol(ot, p, f, k, t, r, 0.0)
+}
+
+/*
+--------------------------------------------------------------------------
+B1976ShiftedImpliedVol -- Implied volatility of the shifted-lognormal
+Black (1976) pricing model
+
+Description:
+A function that returns the lognormal volatility of the shifted forward
+price at which the B1976Shifted method reprices an option on a forward or
+futures contract to the target price p. It returns an *InputError if
+f+shift or k+shift is not positive, or if any argument is not a finite
+number, and otherwise the same errors as GBSMImpliedVol.
+
+Usage:
+vol, err := analytical.B1976ShiftedImpliedVol(ot, p, f, k, t, r, shift)
+
+Arguments:
+ot    option type (either options.Call or options.Put from
+      the options package)
+p     target price of the option
+f     forward price of the underlying instrument
+k     strike price of the option
+t     time to expiry of the option
+r     risk-free rate
+shift shift of the forward and strike prices
+--------------------------------------------------------------------------
+*/
+func B1976ShiftedImpliedVol(ot OptionType, p float64, f float64, k float64, t float64, r float64, shift float64) (float64, error) {
+	in := []field{{"ot", float64(ot)}, {"p", p}, {"f", f}, {"k", k}, {"t", t}, {"r", r}, {"shift", shift}}
+	if err := checkInputs("B1976ShiftedImpliedVol", in); err != nil {
+		return NaN(), err
+	}
+	if f+shift <= 0.0 {
+		return NaN(), &InputError{"B1976ShiftedImpliedVol", "f", f, "must be above -shift", inputMap(in)}
+	}
+	if k+shift <= 0.0 {
+		return NaN(), &InputError{"B1976ShiftedImpliedVol", "k", k, "must be above -shift", inputMap(in)}
+	}
+	return B1976ImpliedVol(ot, p, f+shift, k+shift, t, r)
+}
+
+/*
+------------------------------------------------------------------------
+A1982ImpliedVol -- Implied volatility of the Asay (1982) pricing model
+
+Description:
+A function that returns the volatility at which the A1982 method
+reprices an option on a futures contract where the premium is fully
+margined to the target price p. It returns the same errors as
+GBSMImpliedVol.
+
+Usage:
+vol, err := analytical.A1982ImpliedVol(ot, p, f, k, t)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+p  target price of the option
+f  forward price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+------------------------------------------------------------------------
+*/
+func A1982ImpliedVol(ot OptionType, p float64, f float64, k float64, t float64) (float64, error) {
+	return GBSMImpliedVol(ot, p, f, k, t, 0.0, 0.0)
+}
+
+/*
+--------------------------------------------------------------------------
+GK1983ImpliedVol -- Implied volatility of the Garman and Kohlhagen (1983)
+pricing model
+
+Description:
+A function that returns the volatility at which the GK1983 method
+reprices a currency option to the target price p. It returns the same
+errors as GBSMImpliedVol.
+
+Usage:
+vol, err := analytical.GK1983ImpliedVol(ot, p, s, k, t, rd, rf)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+p  target price of the option
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+rd domestic risk-free rate (base currency)
+rf foreign risk-free rate (quoted currency)
+--------------------------------------------------------------------------
+*/
+func GK1983ImpliedVol(ot OptionType, p float64, s float64, k float64, t float64, rd float64, rf float64) (float64, error) {
+	return GBSMImpliedVol(ot, p, s, k, t, rd, rd-rf)
+}
+
+/*
+--------------------------------------------------------------------
+BV2002ImpliedVol -- Implied volatility of the Bos and Vandermark
+(2002) pricing model
+
+Description:
+A function that returns the volatility at which the BV2002 method
+reprices an option on a stock that pays discrete cash dividends to
+the target price p. It returns the same errors as GBSMImpliedVol.
+
+Usage:
+vol, err := analytical.BV2002ImpliedVol(ot, p, s, k, t, r, dl)
+
+Arguments:
+ot option type (either options.Call or options.Put from
+   the options package)
+p  target price of the option
+s  spot price of the underlying instrument
+k  strike price of the option
+t  time to expiry of the option
+r  risk-free rate
+dl discrete dividend list (the equity.DivList type
+   in the equity package)
+--------------------------------------------------------------------
+*/
+func BV2002ImpliedVol(ot OptionType, p float64, s float64, k float64, t float64, r float64, dl DivList) (float64, error) {
+	rc := curves.FlatCurve{Rate: r}
+	adjS := s - divNear(rc, t, dl)
+	adjK := k + (divFar(rc, t, dl) / rc.DiscountFactor(t))
+	return BS1973ImpliedVol(ot, p, adjS, adjK, t, r)
+}
@@ -0,0 +1,181 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"errors"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+	"testing"
+)
+
+func TestImpliedVolRoundTrip(t *testing.T) {
+	dl := DivList{Div{TimeToDividend: 0.25, Amount: 2.0}, Div{TimeToDividend: 0.75, Amount: 2.0}}
+	models := []struct {
+		name  string
+		price func(ot OptionType, k float64, v float64) (float64, error)
+		solve func(ot OptionType, p float64, k float64) (float64, error)
+	}{
+		{"GBSM",
+			func(ot OptionType, k float64, v float64) (float64, error) {
+				var out ModelOutputs
+				return out.Value, out.GBSM(ot, 100.0, k, 0.5, v, 0.05, 0.02)
+			},
+			func(ot OptionType, p float64, k float64) (float64, error) {
+				return GBSMImpliedVol(ot, p, 100.0, k, 0.5, 0.05, 0.02)
+			}},
+		{"BS1973",
+			func(ot OptionType, k float64, v float64) (float64, error) {
+				var out ModelOutputs
+				return out.Value, out.BS1973(ot, 100.0, k, 0.5, v, 0.05)
+			},
+			func(ot OptionType, p float64, k float64) (float64, error) {
+				return BS1973ImpliedVol(ot, p, 100.0, k, 0.5, 0.05)
+			}},
+		{"M1973",
+			func(ot OptionType, k float64, v float64) (float64, error) {
+				var out ModelOutputs
+				return out.Value, out.M1973(ot, 100.0, k, 0.5, v, 0.05, 0.03)
+			},
+			func(ot OptionType, p float64, k float64) (float64, error) {
+				return M1973ImpliedVol(ot, p, 100.0, k, 0.5, 0.05, 0.03)
+			}},
+		{"B1976",
+			func(ot OptionType, k float64, v float64) (float64, error) {
+				var out ModelOutputs
+				return out.Value, out.B1976(ot, 100.0, k, 0.5, v, 0.05)
+			},
+			func(ot OptionType, p float64, k float64) (float64, error) {
+				return B1976ImpliedVol(ot, p, 100.0, k, 0.5, 0.05)
+			}},
+		{"A1982",
+			func(ot OptionType, k float64, v float64) (float64, error) {
+				var out ModelOutputs
+				return out.Value, out.A1982(ot, 100.0, k, 0.5, v)
+			},
+			func(ot OptionType, p float64, k float64) (float64, error) {
+				return A1982ImpliedVol(ot, p, 100.0, k, 0.5)
+			}},
+		{"GK1983",
+			func(ot OptionType, k float64, v float64) (float64, error) {
+				var out ModelOutputs
+				return out.Value, out.GK1983(ot, 100.0, k, 0.5, v, 0.05, 0.07)
+			},
+			func(ot OptionType, p float64, k float64) (float64, error) {
+				return GK1983ImpliedVol(ot, p, 100.0, k, 0.5, 0.05, 0.07)
+			}},
+		{"BV2002",
+			func(ot OptionType, k float64, v float64) (float64, error) {
+				var out ModelOutputs
+				return out.Value, out.BV2002(ot, 100.0, k, 1.0, v, 0.05, dl)
+			},
+			func(ot OptionType, p float64, k float64) (float64, error) {
+				return BV2002ImpliedVol(ot, p, 100.0, k, 1.0, 0.05, dl)
+			}},
+	}
+	for _, m := range models {
+		for _, ot := range []OptionType{Call, Put} {
+			for _, k := range []float64{80.0, 100.0, 125.0} {
+				for _, v := range []float64{0.15, 0.3, 1.2} {
+					p, err := m.price(ot, k, v)
+					if err != nil {
+						t.Fatal(err)
+					}
+					vol, err := m.solve(ot, p, k)
+					if err != nil {
+						t.Fatalf("%sImpliedVol(%v, k=%v, v=%v): %v", m.name, ot, k, v, err)
+					}
+					if Abs(vol-v) > 1.0e-8*v {
+						t.Errorf("%sImpliedVol(%v, k=%v) = %v, want %v", m.name, ot, k, vol, v)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGBSMImpliedVolTinyPremium(t *testing.T) {
+	// Far out of the money, the premium is far below 1, and must still be
+	// matched to the relative tolerance of the solver.
+	for _, p := range []float64{1.0e-13, 1.0e-10, 1.0e-6} {
+		vol, err := GBSMImpliedVol(Call, p, 100.0, 300.0, 0.25, 0.05, 0.05)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out ModelOutputs
+		if err := out.GBSM(Call, 100.0, 300.0, 0.25, vol, 0.05, 0.05); err != nil {
+			t.Fatal(err)
+		}
+		if Abs(out.Value-p) > 1.0e-9*p {
+			t.Errorf("GBSMImpliedVol(p=%v) = %v, which reprices at %v", p, vol, out.Value)
+		}
+	}
+}
+
+func TestGBSMImpliedVolBounds(t *testing.T) {
+	// The call is bounded by its discounted intrinsic value of the forward
+	// below, and by the discounted forward price above.
+	fwdS, fwdK := 100.0*Exp((0.02-0.05)*0.5), 90.0*Exp(-0.05*0.5)
+	if _, err := GBSMImpliedVol(Call, 0.99*(fwdS-fwdK), 100.0, 90.0, 0.5, 0.05, 0.02); err == nil {
+		t.Error("GBSMImpliedVol below the intrinsic value returned no error")
+	} else if _, ok := err.(ErrBelowIntrinsic); !ok {
+		t.Errorf("GBSMImpliedVol below the intrinsic value returned %v, want ErrBelowIntrinsic", err)
+	}
+	if _, err := GBSMImpliedVol(Call, fwdS, 100.0, 90.0, 0.5, 0.05, 0.02); err == nil {
+		t.Error("GBSMImpliedVol at the upper bound returned no error")
+	} else if _, ok := err.(ErrAboveUpperBound); !ok {
+		t.Errorf("GBSMImpliedVol at the upper bound returned %v, want ErrAboveUpperBound", err)
+	}
+	if _, err := GBSMImpliedVol(Put, 1.01*fwdK, 100.0, 90.0, 0.5, 0.05, 0.02); err == nil {
+		t.Error("GBSMImpliedVol above the upper bound of the put returned no error")
+	} else if _, ok := err.(ErrAboveUpperBound); !ok {
+		t.Errorf("GBSMImpliedVol above the upper bound of the put returned %v, want ErrAboveUpperBound", err)
+	}
+}
+
+func TestGBSMImpliedVolInvalidInputs(t *testing.T) {
+	cases := []struct {
+		ot            OptionType
+		p, s, k, t, b float64
+		field         string
+	}{
+		{Call, 10.0, -100.0, 100.0, 0.5, 0.02, "s"},
+		{Call, 10.0, 100.0, 0.0, 0.5, 0.02, "k"},
+		{Call, 10.0, 100.0, 100.0, 0.0, 0.02, "t"},
+		{Call, NaN(), 100.0, 100.0, 0.5, 0.02, "p"},
+		{Call, 10.0, 100.0, 100.0, 0.5, Inf(1), "b"},
+		{OptionType(2), 10.0, 100.0, 100.0, 0.5, 0.02, "ot"},
+	}
+	for _, c := range cases {
+		_, err := GBSMImpliedVol(c.ot, c.p, c.s, c.k, c.t, 0.05, c.b)
+		var ie *InputError
+		if !errors.As(err, &ie) || ie.Field != c.field {
+			t.Errorf("GBSMImpliedVol(%v, p=%v, s=%v, k=%v, t=%v, b=%v) returned %v, want an *InputError for %s", c.ot, c.p, c.s, c.k, c.t, c.b, err, c.field)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "github.com/kervinlow/quantstruct/math"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+)
+
+/*
+================================================================
+Provides the closed-form pricer for European options with the
+payoffs of the options package under the Generalized Black
+Scholes Merton model.
+================================================================
+*/
+
+/*
+--------------------------------------------------------------------------
+GBSMPayoff -- Generalized Black Scholes Merton pricing model for payoffs
+
+Description:
+A method that computes the theoretical value and greeks of a European
+option with the given payoff under the same dynamics as the GBSM method,
+and saves the computed results in the fields of the ModelOutputs
+receiver. The value is given in closed form for the options.Vanilla,
+options.CashOrNothing, options.AssetOrNothing, options.Gap, options.Power,
+options.Straddle and options.Strangle payoffs, and for the options.Capped
+and options.Floored payoffs of an options.Vanilla payoff; the greeks are
+computed by finite differences, with the same market conventions as the
+GBSM method. It returns an *InputError if s, t or v is not positive or if
+any argument is not a finite number, the error returned by the Validate
+method of the payoff if it is invalid, the error ErrUnsupportedContract if
+the payoff is not one of these, or an *OutputError if the value or a greek
+is not a finite number; otherwise, it returns nil.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.GBSMPayoff(p, s, t, v, r, b)
+
+Arguments:
+p  payoff of the option (any type that implements the
+   options.Payoff interface in the options package)
+s  spot price of the underlying instrument
+t  time to expiry of the option
+v  volatility of the underlying instrument
+r  risk-free rate
+b  cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) GBSMPayoff(p Payoff, s float64, t float64, v float64, r float64, b float64) error {
+	in := []field{{"s", s}, {"t", t}, {"v", v}, {"r", r}, {"b", b}}
+	if err := checkInputs("GBSMPayoff", in, "s", "t", "v"); err != nil {
+		return err
+	}
+	if p != nil {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	if _, err := getGBSMPayoffValue(p, s, t, v, r, b); err != nil {
+		return err
+	}
+	price := func(s, t, v, r, b float64) float64 {
+		value, _ := getGBSMPayoffValue(p, s, t, v, r, b)
+		return value
+	}
+	return out.numericalGreeks("GBSMPayoff", price, s, t, v, r, b)
+}
+
+/*
+getGBSMPayoffValue is an unexported function that returns the theoretical
+value of a European option with the payoff p under the Generalized Black
+Scholes Merton model, or the error ErrUnsupportedContract if the payoff
+has no closed form.
+*/
+func getGBSMPayoffValue(p Payoff, s float64, t float64, v float64, r float64, b float64) (float64, error) {
+	df, fwd := Exp((-r)*t), s*Exp(b*t)
+	// d returns d1 and d2 for the strike price k.
+	d := func(k float64) (float64, float64) {
+		d1 := (Log(fwd/k) + (v*v/2.0)*t) / (v * Sqrt(t))
+		return d1, d1 - v*Sqrt(t)
+	}
+	vanilla := func(ot OptionType, k float64) float64 {
+		d1, d2 := d(k)
+		phi := vanillaSign(Vanilla{Type: ot, Strike: k})
+		return phi * df * (fwd*CDF(phi*d1) - k*CDF(phi*d2))
+	}
+	switch p := p.(type) {
+	case Vanilla:
+		return vanilla(p.Type, p.Strike), nil
+	case CashOrNothing:
+		_, d2 := d(p.Strike)
+		if p.Type == Put {
+			return df * p.Cash * CDF(-d2), nil
+		}
+		return df * p.Cash * CDF(d2), nil
+	case AssetOrNothing:
+		d1, _ := d(p.Strike)
+		if p.Type == Put {
+			return df * fwd * CDF(-d1), nil
+		}
+		return df * fwd * CDF(d1), nil
+	case Gap:
+		// The exercise is decided by the trigger price, and the strike
+		// price is paid.
+		d1, d2 := d(p.Trigger)
+		if p.Type == Put {
+			return df * (p.Strike*CDF(-d2) - fwd*CDF(-d1)), nil
+		}
+		return df * (fwd*CDF(d1) - p.Strike*CDF(d2)), nil
+	case Power:
+		if !(p.Exponent > 0.0) {
+			break
+		}
+		// The spot price raised to the power i is lognormal with volatility
+		// i * v, and its forward price follows from the lognormal moments.
+		i := p.Exponent
+		fwdI := Pow(s, i) * Exp(i*(b-v*v/2.0)*t+i*i*v*v*t/2.0)
+		d1 := (Log(fwdI/p.Strike) + (i*i*v*v/2.0)*t) / (i * v * Sqrt(t))
+		d2 := d1 - i*v*Sqrt(t)
+		if p.Type == Put {
+			return df * (p.Strike*CDF(-d2) - fwdI*CDF(-d1)), nil
+		}
+		return df * (fwdI*CDF(d1) - p.Strike*CDF(d2)), nil
+	case Straddle:
+		return vanilla(Call, p.Strike) + vanilla(Put, p.Strike), nil
+	case Strangle:
+		return vanilla(Put, p.PutStrike) + vanilla(Call, p.CallStrike), nil
+	case Capped:
+		inner, ok := p.Payoff.(Vanilla)
+		if !ok {
+			break
+		}
+		// A capped call is a call spread; a capped put is a put spread
+		// unless the cap exceeds the strike price.
+		switch {
+		case p.Cap <= 0.0:
+			return df * p.Cap, nil
+		case inner.Type == Put && inner.Strike-p.Cap <= 0.0:
+			return vanilla(Put, inner.Strike), nil
+		case inner.Type == Put:
+			return vanilla(Put, inner.Strike) - vanilla(Put, inner.Strike-p.Cap), nil
+		}
+		return vanilla(Call, inner.Strike) - vanilla(Call, inner.Strike+p.Cap), nil
+	case Floored:
+		inner, ok := p.Payoff.(Vanilla)
+		if !ok {
+			break
+		}
+		// A floored option pays the floor plus an option struck further
+		// out of the money by the floor.
+		switch {
+		case p.Floor <= 0.0:
+			return vanilla(inner.Type, inner.Strike), nil
+		case inner.Type == Put && inner.Strike-p.Floor <= 0.0:
+			return df * p.Floor, nil
+		case inner.Type == Put:
+			return df*p.Floor + vanilla(Put, inner.Strike-p.Floor), nil
+		}
+		return df*p.Floor + vanilla(Call, inner.Strike+p.Floor), nil
+	}
+	return NaN(), ErrUnsupportedContract("No analytical pricer can value the payoff.")
+}
+
+/*
+vanillaSign is an unexported function that returns the sign phi of a valid
+vanilla payoff, i.e. 1 for a call and -1 for a put, by which the
+Black-Scholes-Merton formulas for a call give those for a put, e.g. the
+value phi * (F * N(phi * d1) - K * N(phi * d2)) discounted.
+*/
+func vanillaSign(p Vanilla) float64 {
+	if p.Type == Put {
+		return -1.0
+	}
+	return 1.0
+}
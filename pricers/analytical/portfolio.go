@@ -0,0 +1,160 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"context"
+	. "github.com/kervinlow/quantstruct/options"
+	"runtime"
+	"sync"
+)
+
+/*
+=============================================================
+Provides the portfolio pricer that values many option contracts
+concurrently on a bounded pool of workers.
+=============================================================
+*/
+
+/*
+Position represents a holding of an option contract in a portfolio. The
+results of a position are those of the Price method multiplied by its
+Quantity, which is negative for a short position. If Conventions is nil,
+the greeks are scaled by DefaultConventions().
+
+Usage (example):
+var p = analytical.Position{Option: o, MarketData: md, Quantity: -10.0}
+*/
+type Position struct {
+	Option      Option
+	MarketData  MarketData
+	Quantity    float64
+	Conventions *Conventions
+}
+
+/*
+Result represents the results of pricing a position of a portfolio. Err
+is nil if the position was priced; otherwise, it holds the error returned
+by the Price method, or the error of the context if the position was not
+priced because the context was cancelled or its deadline was exceeded.
+*/
+type Result struct {
+	Outputs ModelOutputs
+	Err     error
+}
+
+/*
+--------------------------------------------------------------------------
+PricePortfolio -- Portfolio pricer
+
+Description:
+A function that values the positions of a portfolio with the Price method
+on a pool of at most workers goroutines, and returns one result per
+position, in the order of the positions. Each worker prices its positions
+sequentially, as the Price method and the GBSM kernel that it shares with
+GBSMChain start no goroutines of their own, so the concurrency is bounded
+by workers. A position that cannot be priced has its error in its result
+and does not stop the other positions from being priced. If the context
+is cancelled or its deadline is exceeded, no further positions are
+priced, and the results of the positions that were not priced hold the
+error of the context. If workers is not positive, the number of workers
+is runtime.GOMAXPROCS(0). It returns the error of the context if the
+context is done before all the positions are priced; otherwise, it
+returns nil.
+
+Usage:
+results, err := analytical.PricePortfolio(ctx, positions, workers)
+
+Arguments:
+ctx       context of the pricing (the context.Context type)
+positions positions of the portfolio (the analytical.Position type)
+workers   maximum number of workers
+--------------------------------------------------------------------------
+*/
+func PricePortfolio(ctx context.Context, positions []Position, workers int) ([]Result, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(positions) {
+		workers = len(positions)
+	}
+	results := make([]Result, len(positions))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	// Start the workers, each of which prices the positions it receives until the jobs channel is closed.
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = pricePosition(ctx, positions[i])
+			}
+		}()
+	}
+	// Send the positions to the workers, and mark the unsent positions when the context is done.
+	next := 0
+send:
+	for ; next < len(positions); next++ {
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	for i := next; i < len(positions); i++ {
+		results[i].Err = ctx.Err()
+	}
+	// The context only fails the call if it stopped a position from being priced.
+	if err := ctx.Err(); err != nil {
+		for _, res := range results {
+			if res.Err == err {
+				return results, err
+			}
+		}
+	}
+	return results, nil
+}
+
+/*
+pricePosition is an unexported function that returns the result of
+pricing a position of a portfolio sequentially on the calling worker,
+unless the context is already done.
+*/
+func pricePosition(ctx context.Context, p Position) Result {
+	var res Result
+	if err := ctx.Err(); err != nil {
+		res.Err = err
+		return res
+	}
+	res.Outputs.Conventions = p.Conventions
+	if res.Err = res.Outputs.Price(p.Option, p.MarketData); res.Err == nil {
+		res.Outputs.multiply(p.Quantity)
+	}
+	return res
+}
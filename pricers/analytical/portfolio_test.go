@@ -0,0 +1,105 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"context"
+	"errors"
+	. "github.com/kervinlow/quantstruct/options"
+	"testing"
+	"time"
+)
+
+/*
+testPositions returns positions in calls and puts at 20 strikes, with an
+invalid contract (no currency) as the last position.
+*/
+func testPositions() []Position {
+	md := MarketData{
+		ValuationDate: time.Date(2016, 6, 30, 0, 0, 0, 0, time.UTC),
+		Spot:          100.0,
+		Volatility:    0.3,
+		Rate:          0.05,
+		Carry:         0.02,
+	}
+	var positions []Position
+	for i := 0; i < 20; i++ {
+		o := Option{
+			Underlying: "XYZ",
+			Type:       Call,
+			Strike:     80.0 + 2.0*float64(i),
+			Expiry:     time.Date(2016, 12, 16, 0, 0, 0, 0, time.UTC),
+			Exercise:   European,
+			Settlement: CashSettled,
+			Multiplier: 100.0,
+			Currency:   "USD",
+		}
+		if i%2 == 1 {
+			o.Type, o.Exercise = Put, American
+		}
+		positions = append(positions, Position{Option: o, MarketData: md, Quantity: float64(i - 10)})
+	}
+	invalid := positions[0]
+	invalid.Option.Currency = ""
+	return append(positions, invalid)
+}
+
+func TestPricePortfolio(t *testing.T) {
+	positions := testPositions()
+	results, err := PricePortfolio(context.Background(), positions, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range positions[:len(positions)-1] {
+		var want ModelOutputs
+		if err := want.Price(p.Option, p.MarketData); err != nil {
+			t.Fatal(err)
+		}
+		want.multiply(p.Quantity)
+		if results[i].Err != nil || results[i].Outputs != want {
+			t.Errorf("PricePortfolio[%d] = %+v (%v), want %+v", i, results[i].Outputs, results[i].Err, want)
+		}
+	}
+	var e ErrInvalidOption
+	if !errors.As(results[len(positions)-1].Err, &e) {
+		t.Errorf("PricePortfolio of an invalid contract returned %v, want ErrInvalidOption", results[len(positions)-1].Err)
+	}
+}
+
+func TestPricePortfolioCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := PricePortfolio(ctx, testPositions(), 4)
+	if err != context.Canceled {
+		t.Errorf("PricePortfolio with a cancelled context returned %v, want context.Canceled", err)
+	}
+	for i, res := range results {
+		if res.Err != context.Canceled {
+			t.Errorf("PricePortfolio[%d] with a cancelled context has the error %v", i, res.Err)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	"fmt"
+	. "github.com/kervinlow/quantstruct/math"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+)
+
+/*
+=================================================================
+Provides the SABR stochastic volatility model of Hagan et al.
+(2002) through its implied volatility expansions, and their
+calibration to the volatility smile of a single expiry.
+=================================================================
+*/
+
+/*
+SABRFormula enumerates the implied volatility expansions of the SABR model:
+the lognormal (Black) volatility of Hagan et al. (2002) (SABRLognormal),
+the normal (Bachelier) volatility of Hagan et al. (2002) (SABRNormal), and
+the lognormal volatility with the leading term corrected by Obloj (2008)
+(SABRObloj), which is more accurate far from the money.
+*/
+type SABRFormula int
+
+const (
+	SABRLognormal SABRFormula = iota
+	SABRNormal
+	SABRObloj
+)
+
+/*
+SABRParams is the structure that holds the parameters of the SABR model,
+as taken by the H2002Vol function.
+*/
+type SABRParams struct {
+	Alpha float64 // initial volatility
+	Beta  float64 // exponent of the forward price in its volatility
+	Rho   float64 // correlation between the forward price and its volatility
+	Nu    float64 // volatility of the volatility
+}
+
+/*
+SABRFit is the structure that holds the result of a calibration of the
+SABR model: the calibrated parameters, the residuals in the order of the
+market quotes, and the root mean square of the residuals.
+*/
+type SABRFit struct {
+	Params    SABRParams
+	Residuals []QuoteResidual
+	RMSE      float64
+}
+
+/*
+SABRCalibrationConfig is the structure that holds the settings of a
+calibration of the SABR model. Tolerance and MaxIterations are passed to
+the math.LevenbergMarquardt optimizer.
+
+Usage (example):
+var cfg = analytical.DefaultSABRCalibrationConfig()
+cfg.MaxIterations = 1000
+*/
+type SABRCalibrationConfig struct {
+	Tolerance     float64
+	MaxIterations int
+}
+
+/*
+DefaultSABRCalibrationConfig returns the default settings of a calibration
+of the SABR model.
+*/
+func DefaultSABRCalibrationConfig() SABRCalibrationConfig {
+	return SABRCalibrationConfig{
+		Tolerance:     1.0e-12,
+		MaxIterations: 500,
+	}
+}
+
+/*
+--------------------------------------------------------------------------
+H2002Vol -- Hagan et al. (2002) SABR implied volatility
+
+Description:
+A function that returns the implied volatility of a European option under
+the SABR model, in which the forward price f has the stochastic volatility
+alpha*f^beta whose own volatility is nu, correlated with the forward price
+by rho. The formula fm selects the expansion, and the volatility is
+lognormal, for B1976, or normal, for B1900, accordingly. It returns an
+*InputError if the formula is not one of the SABRFormula values, if f, k
+or alpha is not positive, if t or nu is negative, if beta is not between 0
+and 1, if rho is not strictly between -1 and 1, or if any argument is not
+a finite number; otherwise, it returns nil.
+
+Usage:
+var v, err = analytical.H2002Vol(fm, f, k, t, alpha, beta, rho, nu)
+
+Arguments:
+fm    implied volatility expansion (analytical.SABRLognormal,
+      analytical.SABRNormal or analytical.SABRObloj)
+f     forward price of the underlying instrument
+k     strike price of the option
+t     time to expiry of the option
+alpha initial volatility of the forward price
+beta  exponent of the forward price in its volatility
+rho   correlation between the forward price and its volatility
+nu    volatility of the volatility
+--------------------------------------------------------------------------
+*/
+func H2002Vol(fm SABRFormula, f float64, k float64, t float64, alpha float64, beta float64, rho float64, nu float64) (float64, error) {
+	if _, err := checkH2002Inputs("H2002Vol", fm, f, k, t, alpha, beta, rho, nu, nil); err != nil {
+		return NaN(), err
+	}
+	return getH2002Vol(fm, f, k, t, alpha, beta, rho, nu), nil
+}
+
+/*
+--------------------------------------------------------------------------
+H2002 -- Hagan et al. (2002) SABR pricing model
+
+Description:
+A method that computes the theoretical value and greeks of a European
+option on a forward under the SABR model, and saves the computed results
+in the fields of the ModelOutputs receiver. The option is valued with B1976
+at the lognormal volatility of the SABRLognormal or SABRObloj expansion, or
+with B1900 at the normal volatility of the SABRNormal expansion, as given
+by H2002Vol. The greeks are those of B1976 or B1900 with the implied
+volatility held fixed, i.e. they do not include the move of the smile with
+the forward price. It returns the errors of H2002Vol, with an *InputError
+also if the option type is neither Call nor Put or r is not a finite
+number, and otherwise the errors of B1976 or B1900.
+
+Usage:
+var out analytical.ModelOutputs
+err := out.H2002(fm, ot, f, k, t, r, alpha, beta, rho, nu)
+
+Arguments:
+fm    implied volatility expansion (analytical.SABRLognormal,
+      analytical.SABRNormal or analytical.SABRObloj)
+ot    option type (either options.Call or options.Put from
+      the options package)
+f     forward price of the underlying instrument
+k     strike price of the option
+t     time to expiry of the option
+r     risk-free rate
+alpha initial volatility of the forward price
+beta  exponent of the forward price in its volatility
+rho   correlation between the forward price and its volatility
+nu    volatility of the volatility
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) H2002(fm SABRFormula, ot OptionType, f float64, k float64, t float64, r float64, alpha float64, beta float64, rho float64, nu float64) error {
+	extra := []field{{"ot", float64(ot)}, {"r", r}}
+	in, err := checkH2002Inputs("H2002", fm, f, k, t, alpha, beta, rho, nu, extra)
+	if err != nil {
+		return err
+	}
+	if err := checkOptionType("H2002", ot, in); err != nil {
+		return err
+	}
+	v := getH2002Vol(fm, f, k, t, alpha, beta, rho, nu)
+	if fm == SABRNormal {
+		return out.B1900(ot, f, k, t, v, r)
+	}
+	return out.B1976(ot, f, k, t, v, r)
+}
+
+/*
+--------------------------------------------------------------------------
+CalibrateH2002 -- Calibration of the SABR model to a single expiry
+
+Description:
+A function that finds the alpha, rho and nu of the SABR model, with beta
+held fixed at guess.Beta, that best fit the given market quotes of
+European options of a single expiry on the forward price f. The quoted
+volatilities are lognormal for the SABRLognormal and SABRObloj formulas,
+or normal for the SABRNormal formula. The differences between the model
+and market volatilities are minimized in the least-squares sense with the
+Levenberg-Marquardt method from the initial guess; alpha is kept
+positive, nu non-negative, and rho strictly between -1 and 1. The
+calibration is deterministic for given arguments. It returns the errors
+of H2002Vol for the forward price and the guess, the error
+ErrInvalidCalibration if there are no quotes, if a quote is not positive
+or finite, if the quotes are not all of the same expiry, or if the
+settings are invalid, or the error ErrNoConvergence, together with the
+best fit found, if the optimizer does not converge within the maximum
+number of iterations; otherwise, it returns nil.
+
+Usage:
+var fit, err = analytical.CalibrateH2002(fm, quotes, f, guess, cfg)
+
+Arguments:
+fm     implied volatility expansion (analytical.SABRLognormal,
+       analytical.SABRNormal or analytical.SABRObloj)
+quotes market quotes of European options (a slice of
+       analytical.VolQuote)
+f      forward price of the underlying instrument
+guess  initial guess of the parameters, and the fixed beta
+       (the analytical.SABRParams type)
+cfg    settings of the calibration (the
+       analytical.SABRCalibrationConfig type)
+--------------------------------------------------------------------------
+*/
+func CalibrateH2002(fm SABRFormula, quotes []VolQuote, f float64, guess SABRParams, cfg SABRCalibrationConfig) (SABRFit, error) {
+	if !(cfg.Tolerance > 0.0) || cfg.MaxIterations < 1 {
+		return SABRFit{}, ErrInvalidCalibration("The tolerance and the maximum number of iterations must be positive.")
+	}
+	if len(quotes) == 0 {
+		return SABRFit{}, ErrInvalidCalibration("There are no market quotes.")
+	}
+	t := quotes[0].Expiry
+	for i, q := range quotes {
+		if !(q.Strike > 0.0 && q.Expiry > 0.0 && q.Vol > 0.0) || IsInf(q.Strike, 0) || IsInf(q.Expiry, 0) || IsInf(q.Vol, 0) {
+			return SABRFit{}, ErrInvalidCalibration(fmt.Sprintf("Market quote %d is not positive and finite.", i))
+		}
+		if q.Expiry != t {
+			return SABRFit{}, ErrInvalidCalibration("The market quotes are not all of the same expiry.")
+		}
+	}
+	g := guess
+	if _, err := checkH2002Inputs("CalibrateH2002", fm, f, f, t, g.Alpha, g.Beta, g.Rho, g.Nu, nil); err != nil {
+		return SABRFit{}, err
+	}
+	residuals := func(x []float64) []float64 {
+		res := make([]float64, len(quotes))
+		for i, q := range quotes {
+			res[i] = getH2002Vol(fm, f, q.Strike, t, x[0], g.Beta, x[1], x[2]) - q.Vol
+		}
+		return res
+	}
+	lower := []float64{1.0e-12 * g.Alpha, -0.9999, 0.0}
+	upper := []float64{Inf(1), 0.9999, Inf(1)}
+	x, err := LevenbergMarquardt(residuals, []float64{g.Alpha, g.Rho, g.Nu}, lower, upper, cfg.Tolerance, cfg.MaxIterations)
+	if err != nil {
+		err = ErrNoConvergence("Calibration did not converge.")
+	}
+	fit := SABRFit{Params: SABRParams{x[0], g.Beta, x[1], x[2]}, Residuals: make([]QuoteResidual, len(quotes))}
+	var sum float64
+	for i, e := range residuals(x) {
+		fit.Residuals[i] = QuoteResidual{quotes[i], quotes[i].Vol + e, e}
+		sum += e * e
+	}
+	fit.RMSE = Sqrt(sum / float64(len(quotes)))
+	return fit, err
+}
+
+/*
+checkH2002Inputs is an unexported function that returns the fields of the
+arguments of a SABR function, with the given extra fields, together with
+an *InputError if they are invalid as described for H2002Vol, or nil.
+*/
+func checkH2002Inputs(pricer string, fm SABRFormula, f float64, k float64, t float64, alpha float64, beta float64, rho float64, nu float64, extra []field) ([]field, error) {
+	in := append([]field{{"fm", float64(fm)}, {"f", f}, {"k", k}, {"t", t}, {"alpha", alpha}, {"beta", beta}, {"rho", rho}, {"nu", nu}}, extra...)
+	if fm != SABRLognormal && fm != SABRNormal && fm != SABRObloj {
+		return in, &InputError{pricer, "fm", float64(fm), "must be analytical.SABRLognormal, analytical.SABRNormal or analytical.SABRObloj", inputMap(in)}
+	}
+	if err := checkInputs(pricer, in, "f", "k", "alpha"); err != nil {
+		return in, err
+	}
+	if err := checkNonNegative(pricer, in, "t", "nu"); err != nil {
+		return in, err
+	}
+	if beta < 0.0 || beta > 1.0 {
+		return in, &InputError{pricer, "beta", beta, "must be between 0 and 1", inputMap(in)}
+	}
+	if !(rho > -1.0 && rho < 1.0) {
+		return in, &InputError{pricer, "rho", rho, "must be strictly between -1 and 1", inputMap(in)}
+	}
+	return in, nil
+}
+
+/*
+getH2002Vol is an unexported function that computes the implied volatility
+of a European option under the SABR model with the given expansion.
+*/
+func getH2002Vol(fm SABRFormula, f float64, k float64, t float64, alpha float64, beta float64, rho float64, nu float64) float64 {
+	lfk := Log(f / k)
+	l2, l4 := lfk*lfk, lfk*lfk*lfk*lfk
+	c := 1.0 - beta
+	fk := Pow(f*k, c/2.0) // (f*k)^((1 - beta)/2)
+	z := (nu / alpha) * fk * lfk
+	// Correction of order t, common to the three expansions, except that the
+	// normal one has -beta*(2 - beta) in place of (1 - beta)^2 in its first term.
+	a := c * c
+	if fm == SABRNormal {
+		a = (-beta) * (2.0 - beta)
+	}
+	rate := (a * alpha * alpha / (24.0 * fk * fk)) + (rho * beta * nu * alpha / (4.0 * fk)) + ((2.0 - (3.0 * rho * rho)) * nu * nu / 24.0)
+	corr := 1.0 + (rate * t)
+	switch fm {
+	case SABRNormal:
+		num := 1.0 + (l2 / 24.0) + (l4 / 1920.0)
+		den := 1.0 + (c * c * l2 / 24.0) + (c * c * c * c * l4 / 1920.0)
+		return alpha * Pow(f*k, beta/2.0) * (num / den) * getSABRRatio(z, rho) * corr
+	case SABRObloj:
+		// The leading term is nu*Log(f/k)/x(z), where z is nu/alpha times the
+		// integral of f^(-beta) from k to f, i.e. nu*(f^c - k^c)/(alpha*c);
+		// f^c - k^c is written with Expm1 to stay accurate near the money.
+		m := 1.0
+		if c*lfk != 0.0 {
+			m = c * lfk / Expm1(c*lfk)
+		}
+		lead := alpha * Pow(k, -c) * m
+		return lead * getSABRRatio(nu*lfk/lead, rho) * corr
+	}
+	den := fk * (1.0 + (c * c * l2 / 24.0) + (c * c * c * c * l4 / 1920.0))
+	return (alpha / den) * getSABRRatio(z, rho) * corr
+}
+
+/*
+getSABRRatio is an unexported function that computes z/x(z) of the SABR
+expansions, where x(z) = Log((Sqrt(1 - 2*rho*z + z^2) + z - rho)/(1 - rho)),
+without cancellation for small or large negative z.
+*/
+func getSABRRatio(z float64, rho float64) float64 {
+	if Abs(z) < 1.0e-7 {
+		return 1.0 - (rho * z / 2.0)
+	}
+	q := Sqrt(1.0 - (2.0 * rho * z) + (z * z))
+	var num float64
+	if z-rho >= 0.0 {
+		num = q + z - rho
+	} else {
+		num = (1.0 - (rho * rho)) / (q - z + rho)
+	}
+	return z / Log(num/(1.0-rho))
+}
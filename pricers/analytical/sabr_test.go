@@ -0,0 +1,96 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package analytical
+
+import (
+	. "math"
+	"testing"
+)
+
+func TestH2002VolATM(t *testing.T) {
+	// At the money, the expansions reduce to the at-the-money volatilities of
+	// Hagan et al. (2002), and are continuous in the strike price.
+	const f, T, alpha, beta, rho, nu = 0.03, 2.0, 0.04, 0.5, -0.3, 0.4
+	fc := Pow(f, 1.0-beta)
+	common := (rho * beta * nu * alpha / (4.0 * fc)) + ((2.0 - 3.0*rho*rho) * nu * nu / 24.0)
+	lognormal := (alpha / fc) * (1.0 + ((1.0-beta)*(1.0-beta)*alpha*alpha/(24.0*fc*fc)+common)*T)
+	normal := alpha * Pow(f, beta) * (1.0 + (-beta*(2.0-beta)*alpha*alpha/(24.0*fc*fc)+common)*T)
+	cases := []struct {
+		fm   SABRFormula
+		want float64
+	}{
+		{SABRLognormal, lognormal}, {SABRObloj, lognormal}, {SABRNormal, normal},
+	}
+	for _, c := range cases {
+		for _, k := range []float64{f, f * (1.0 + 1.0e-9)} {
+			vol, err := H2002Vol(c.fm, f, k, T, alpha, beta, rho, nu)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if Abs(vol-c.want) > 1.0e-9*c.want {
+				t.Errorf("H2002Vol(%v, k=%v) = %v, want %v", c.fm, k, vol, c.want)
+			}
+		}
+	}
+}
+
+func TestCalibrateH2002RoundTrip(t *testing.T) {
+	const f, T = 0.03, 2.0
+	want := SABRParams{Alpha: 0.04, Beta: 0.5, Rho: -0.3, Nu: 0.4}
+	for _, fm := range []SABRFormula{SABRLognormal, SABRNormal, SABRObloj} {
+		var quotes []VolQuote
+		for _, k := range []float64{0.015, 0.02, 0.025, 0.03, 0.035, 0.04, 0.05} {
+			vol, err := H2002Vol(fm, f, k, T, want.Alpha, want.Beta, want.Rho, want.Nu)
+			if err != nil {
+				t.Fatal(err)
+			}
+			quotes = append(quotes, VolQuote{Strike: k, Expiry: T, Vol: vol})
+		}
+		guess := SABRParams{Alpha: 0.02, Beta: 0.5, Rho: 0.0, Nu: 0.2}
+		fit, err := CalibrateH2002(fm, quotes, f, guess, DefaultSABRCalibrationConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := fit.Params
+		if Abs(got.Alpha-want.Alpha) > 1.0e-8 || got.Beta != want.Beta || Abs(got.Rho-want.Rho) > 1.0e-6 || Abs(got.Nu-want.Nu) > 1.0e-6 {
+			t.Errorf("CalibrateH2002(%v) = %+v, want %+v", fm, got, want)
+		}
+		if fit.RMSE > 1.0e-10 {
+			t.Errorf("CalibrateH2002(%v) RMSE = %v, want 0", fm, fit.RMSE)
+		}
+	}
+}
+
+func TestCalibrateH2002InvalidConfig(t *testing.T) {
+	quotes := []VolQuote{{Strike: 0.03, Expiry: 1.0, Vol: 0.2}}
+	guess := SABRParams{Alpha: 0.02, Beta: 0.5, Rho: 0.0, Nu: 0.2}
+	for _, cfg := range []SABRCalibrationConfig{{Tolerance: 0.0, MaxIterations: 500}, {Tolerance: 1.0e-12, MaxIterations: 0}} {
+		if _, err := CalibrateH2002(SABRLognormal, quotes, 0.03, guess, cfg); err == nil {
+			t.Errorf("CalibrateH2002 with the settings %+v returned no error", cfg)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package lattice
+
+import (
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	. "math"
+)
+
+/*
+========================================================================
+Provides the binomial tree pricers for valuing European and American
+financial options.
+========================================================================
+*/
+
+/*
+--------------------------------------------------------------------------
+CRR1979 -- Cox, Ross and Rubinstein (1979) binomial tree
+
+Description:
+A method that computes the theoretical value and greeks of a European or
+American option on an underlying instrument that may pay discrete cash
+dividends, and saves the computed results in the fields of the
+ModelOutputs receiver. It returns the error ErrInvalidSteps if n is less
+than 2, the error ErrInvalidPayoff of the options package if p is nil,
+the error returned by the Validate method of the payoff if it is
+invalid, the error ErrUnsupportedExercise if es is neither European nor
+American, an *analytical.InputError if an input is out of its valid
+range or v is too small for the tree probabilities to lie between 0 and
+1, an *analytical.OutputError if the value or a greek is not a finite
+number, or the error returned by analytical.Conventions.Scale if the
+conventions are invalid; otherwise, it returns nil.
+
+Usage:
+var out lattice.ModelOutputs
+err := out.CRR1979(p, es, s, t, v, r, b, dl, n)
+
+Arguments:
+p  payoff of the option (any type that implements the
+   options.Payoff interface in the options package)
+es exercise style (either options.European or
+   options.American from the options package)
+s  spot price of the underlying instrument
+t  time to expiry of the option
+v  volatility of the underlying instrument
+r  risk-free rate
+b  cost of carry
+dl discrete dividend list (the equity.DivList type
+   in the equity package; nil if there is none)
+n  number of time steps
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) CRR1979(p Payoff, es ExerciseStyle, s float64, t float64, v float64, r float64, b float64, dl DivList, n int) error {
+	if n < 2 {
+		return ErrInvalidSteps("The tree needs at least 2 time steps.")
+	}
+	if err := checkContract(p, es); err != nil {
+		return err
+	}
+	in := map[string]float64{"s": s, "t": t, "v": v, "r": r, "b": b, "n": float64(n)}
+	if err := checkInputs("CRR1979", in); err != nil {
+		return err
+	}
+	dt := t / float64(n)
+	u := Exp(v * Sqrt(dt))
+	d := 1.0 / u
+	pu := (Exp(b*dt) - d) / (u - d)
+	return out.binomial("CRR1979", in, p, es, s, t, r, dl, n, u, d, pu)
+}
+
+/*
+--------------------------------------------------------------------------
+JR1983 -- Jarrow and Rudd (1983) binomial tree
+
+Description:
+A method that computes the theoretical value and greeks of a European or
+American option on an underlying instrument that may pay discrete cash
+dividends using the equal-probability binomial tree, and saves the
+computed results in the fields of the ModelOutputs receiver. It returns
+the error ErrInvalidSteps if n is less than 2, the error
+ErrInvalidPayoff of the options package if p is nil, the error returned
+by the Validate method of the payoff if it is invalid, the error
+ErrUnsupportedExercise if es is neither European nor American, an
+*analytical.InputError if an input is out of its valid range, an
+*analytical.OutputError if the value or a greek is not a finite number,
+or the error returned by analytical.Conventions.Scale if the conventions
+are invalid; otherwise, it returns nil.
+
+Usage:
+var out lattice.ModelOutputs
+err := out.JR1983(p, es, s, t, v, r, b, dl, n)
+
+Arguments:
+p  payoff of the option (any type that implements the
+   options.Payoff interface in the options package)
+es exercise style (either options.European or
+   options.American from the options package)
+s  spot price of the underlying instrument
+t  time to expiry of the option
+v  volatility of the underlying instrument
+r  risk-free rate
+b  cost of carry
+dl discrete dividend list (the equity.DivList type
+   in the equity package; nil if there is none)
+n  number of time steps
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) JR1983(p Payoff, es ExerciseStyle, s float64, t float64, v float64, r float64, b float64, dl DivList, n int) error {
+	if n < 2 {
+		return ErrInvalidSteps("The tree needs at least 2 time steps.")
+	}
+	if err := checkContract(p, es); err != nil {
+		return err
+	}
+	in := map[string]float64{"s": s, "t": t, "v": v, "r": r, "b": b, "n": float64(n)}
+	if err := checkInputs("JR1983", in); err != nil {
+		return err
+	}
+	dt := t / float64(n)
+	u := Exp((b-v*v/2.0)*dt + v*Sqrt(dt))
+	d := Exp((b-v*v/2.0)*dt - v*Sqrt(dt))
+	return out.binomial("JR1983", in, p, es, s, t, r, dl, n, u, d, 0.5)
+}
+
+/*
+--------------------------------------------------------------------------
+LR1996 -- Leisen and Reimer (1996) binomial tree
+
+Description:
+A method that computes the theoretical value and greeks of a European or
+American option on an underlying instrument that may pay discrete cash
+dividends using the binomial tree of Leisen and Reimer (with the
+Peizer-Pratt method 2 inversion), and saves the computed results in the
+fields of the ModelOutputs receiver. The tree is centred on the strike and
+requires an odd number of time steps, so an even n is increased by one. It
+returns the error ErrInvalidSteps if n is less than 2, the error
+ErrInvalidPayoff of the options package if p is nil, the error returned by
+the Validate method of the payoff if it is invalid, the error
+ErrUnsupportedExercise if es is neither European nor American, an
+*analytical.InputError if an input is out of its valid range or v is too
+small for the tree probabilities to lie between 0 and 1, an
+*analytical.OutputError if the value or a greek is not a finite number,
+or the error returned by analytical.Conventions.Scale if the conventions
+are invalid; otherwise, it returns nil.
+
+Usage:
+var out lattice.ModelOutputs
+err := out.LR1996(p, es, s, k, t, v, r, b, dl, n)
+
+Arguments:
+p  payoff of the option (any type that implements the
+   options.Payoff interface in the options package)
+es exercise style (either options.European or
+   options.American from the options package)
+s  spot price of the underlying instrument
+k  strike price that the tree is centred on (e.g. the
+   strike price of the option)
+t  time to expiry of the option
+v  volatility of the underlying instrument
+r  risk-free rate
+b  cost of carry
+dl discrete dividend list (the equity.DivList type
+   in the equity package; nil if there is none)
+n  number of time steps
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) LR1996(p Payoff, es ExerciseStyle, s float64, k float64, t float64, v float64, r float64, b float64, dl DivList, n int) error {
+	if n < 2 {
+		return ErrInvalidSteps("The tree needs at least 2 time steps.")
+	}
+	if err := checkContract(p, es); err != nil {
+		return err
+	}
+	if n%2 == 0 {
+		n++
+	}
+	in := map[string]float64{"s": s, "k": k, "t": t, "v": v, "r": r, "b": b, "n": float64(n)}
+	if err := checkInputs("LR1996", in); err != nil {
+		return err
+	}
+	if !(k > 0.0) || IsInf(k, 1) {
+		return &analytical.InputError{Pricer: "LR1996", Field: "k", Value: k, Reason: "must be a positive finite number", Inputs: in}
+	}
+	dt := t / float64(n)
+	d1 := (Log(s/k) + (b+v*v/2.0)*t) / (v * Sqrt(t))
+	d2 := d1 - v*Sqrt(t)
+	pu := peizerPratt(d2, n)
+	u := Exp(b*dt) * peizerPratt(d1, n) / pu
+	d := (Exp(b*dt) - pu*u) / (1.0 - pu)
+	return out.binomial("LR1996", in, p, es, s, t, r, dl, n, u, d, pu)
+}
+
+/*
+peizerPratt is an unexported function that approximates the inverse of the
+binomial distribution by the Peizer-Pratt method 2, as required by the
+Leisen and Reimer (1996) binomial tree with n (odd) time steps.
+*/
+func peizerPratt(z float64, n int) float64 {
+	x := z / (float64(n) + 1.0/3.0 + 0.1/(float64(n)+1.0))
+	h := 0.5 * Sqrt(1.0-Exp(-x*x*(float64(n)+1.0/6.0)))
+	if z < 0.0 {
+		return 0.5 - h
+	}
+	return 0.5 + h
+}
+
+/*
+binomial is an unexported method that values a financial option by
+backward induction on a recombining binomial tree with n time steps, up
+and down factors u and d, and up probability pu, and saves the value and
+the greeks read off the first two time steps in the fields of the
+ModelOutputs receiver. The errors that it returns carry the name of the
+pricing method and its arguments in.
+*/
+func (out *ModelOutputs) binomial(pricer string, in map[string]float64, p Payoff, es ExerciseStyle, s float64, t float64, r float64, dl DivList, n int, u float64, d float64, pu float64) error {
+	if IsNaN(pu) || pu < 0.0 || pu > 1.0 {
+		return &analytical.InputError{Pricer: pricer, Field: "v", Value: in["v"], Reason: "must be large enough for the tree probabilities to lie between 0 and 1", Inputs: in}
+	}
+	dt := t / float64(n)
+	df := Exp((-r) * dt)
+	divs := divSteps(t, n, dl)
+	spot := func(i, j int) float64 {
+		return s * Pow(u, float64(j)) * Pow(d, float64(i-j))
+	}
+	spots := make([]float64, n+1)
+	values := make([]float64, n+1)
+	for j := 0; j <= n; j++ {
+		values[j] = p.Terminal(Max(spot(n, j)-divs[n], 0.0))
+	}
+	var step1, step2 [3]float64
+	if n == 2 {
+		copy(step2[:], values[:3])
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := 0; j <= i; j++ {
+			spots[j] = spot(i, j)
+			values[j] = df * (pu*values[j+1] + (1.0-pu)*values[j])
+			if es == American {
+				values[j] = Max(values[j], p.Terminal(spots[j]))
+			}
+		}
+		// Carry the values over a dividend that goes ex at this time step; the
+		// option may also be exercised just before it.
+		if divs[i] > 0.0 {
+			exDividend(values[:i+1], spots[:i+1], divs[i])
+			if es == American {
+				for j := 0; j <= i; j++ {
+					values[j] = Max(values[j], p.Terminal(spots[j]))
+				}
+			}
+		}
+		// Keep the node values of the first two time steps for the greeks.
+		switch i {
+		case 2:
+			copy(step2[:], values[:3])
+		case 1:
+			copy(step1[:], values[:2])
+		}
+	}
+	out.Value = values[0]
+	out.Delta = (step1[1] - step1[0]) / (spot(1, 1) - spot(1, 0))
+	out.Gamma = ((step2[2]-step2[1])/(spot(2, 2)-spot(2, 1)) -
+		(step2[1]-step2[0])/(spot(2, 1)-spot(2, 0))) /
+		(0.5 * (spot(2, 2) - spot(2, 0)))
+	// The middle node of the second time step need not sit at the spot price
+	// (e.g. in the JR1983 and LR1996 trees), so remove the change in value due
+	// to the change in spot price before reading off Theta.
+	ds := spot(2, 1) - s
+	out.Theta = (step2[1] - values[0] - out.Delta*ds - 0.5*out.Gamma*ds*ds) / (2.0 * dt)
+	return out.check(pricer, in)
+}
@@ -0,0 +1,190 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package lattice
+
+import (
+	"errors"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	. "math"
+	"testing"
+)
+
+/*
+tree values a call or put option with the vanilla payoff with one of the
+tree pricer methods of the package.
+*/
+type tree func(out *ModelOutputs, ot OptionType, es ExerciseStyle, s, k, t, v, r, b float64, dl DivList, n int) error
+
+var trees = []struct {
+	name  string
+	price tree
+	tol   float64 // tolerance of the value with 500 time steps
+}{
+	{"CRR1979", func(out *ModelOutputs, ot OptionType, es ExerciseStyle, s, k, t, v, r, b float64, dl DivList, n int) error {
+		return out.CRR1979(Vanilla{Type: ot, Strike: k}, es, s, t, v, r, b, dl, n)
+	}, 5.0e-3},
+	{"JR1983", func(out *ModelOutputs, ot OptionType, es ExerciseStyle, s, k, t, v, r, b float64, dl DivList, n int) error {
+		return out.JR1983(Vanilla{Type: ot, Strike: k}, es, s, t, v, r, b, dl, n)
+	}, 5.0e-3},
+	{"LR1996", func(out *ModelOutputs, ot OptionType, es ExerciseStyle, s, k, t, v, r, b float64, dl DivList, n int) error {
+		return out.LR1996(Vanilla{Type: ot, Strike: k}, es, s, k, t, v, r, b, dl, n)
+	}, 1.0e-4},
+	{"B1986", func(out *ModelOutputs, ot OptionType, es ExerciseStyle, s, k, t, v, r, b float64, dl DivList, n int) error {
+		return out.B1986(Vanilla{Type: ot, Strike: k}, es, s, t, v, r, b, dl, n)
+	}, 5.0e-3},
+}
+
+func TestTreesEuropeanAgainstGBSM(t *testing.T) {
+	for _, tr := range trees {
+		for _, ot := range []OptionType{Call, Put} {
+			var want analytical.ModelOutputs
+			if err := want.GBSM(ot, 100.0, 110.0, 0.5, 0.25, 0.06, 0.02); err != nil {
+				t.Fatal(err)
+			}
+			var out ModelOutputs
+			if err := tr.price(&out, ot, European, 100.0, 110.0, 0.5, 0.25, 0.06, 0.02, nil, 500); err != nil {
+				t.Fatal(err)
+			}
+			if Abs(out.Value-want.Value) > tr.tol {
+				t.Errorf("%s(%v) value = %v, want %v", tr.name, ot, out.Value, want.Value)
+			}
+			// The greeks read off the nodes of the first time steps.
+			if Abs(out.Delta-want.Delta) > 5.0e-4 {
+				t.Errorf("%s(%v) delta = %v, want %v", tr.name, ot, out.Delta, want.Delta)
+			}
+			if Abs(out.Gamma-want.Gamma) > 5.0e-5 {
+				t.Errorf("%s(%v) gamma = %v, want %v", tr.name, ot, out.Gamma, want.Gamma)
+			}
+			if Abs(out.Theta-want.Theta) > 5.0e-5 {
+				t.Errorf("%s(%v) theta = %v, want %v", tr.name, ot, out.Theta, want.Theta)
+			}
+		}
+	}
+}
+
+func TestTreesAmericanPut(t *testing.T) {
+	// The American put of Hull, Options, Futures, and Other Derivatives, with
+	// s = k = 50, t = 5 months, v = 0.4 and r = 0.1, is worth 4.2842.
+	for _, tr := range trees {
+		var out ModelOutputs
+		if err := tr.price(&out, Put, American, 50.0, 50.0, 5.0/12.0, 0.4, 0.1, 0.1, nil, 1001); err != nil {
+			t.Fatal(err)
+		}
+		if Abs(out.Value-4.2842) > 2.0e-3 {
+			t.Errorf("%s American put = %v, want 4.2842", tr.name, out.Value)
+		}
+	}
+}
+
+func TestTreesDiscreteDividends(t *testing.T) {
+	dl := DivList{}.AddDiv(0.25, 2.0)
+	// With b = r, the European values satisfy the put-call parity with the
+	// spot price net of the present value of the dividend, up to the drift of
+	// the JR1983 tree and the time step that the dividend goes ex at in the
+	// LR1996 tree (which has 201 time steps).
+	fwd := 100.0 - 2.0*Exp(-0.06*0.25) - 100.0*Exp(-0.06*0.5)
+	for _, tr := range trees {
+		var call, put, plain, american ModelOutputs
+		if err := tr.price(&call, Call, European, 100.0, 100.0, 0.5, 0.25, 0.06, 0.06, dl, 200); err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.price(&put, Put, European, 100.0, 100.0, 0.5, 0.25, 0.06, 0.06, dl, 200); err != nil {
+			t.Fatal(err)
+		}
+		if Abs(call.Value-put.Value-fwd) > 5.0e-4 {
+			t.Errorf("%s call - put = %v, want %v", tr.name, call.Value-put.Value, fwd)
+		}
+		// The dividend lowers the value of a call, and the call may be
+		// exercised just before the dividend goes ex.
+		if err := tr.price(&plain, Call, European, 100.0, 100.0, 0.5, 0.25, 0.06, 0.06, nil, 200); err != nil {
+			t.Fatal(err)
+		}
+		if call.Value >= plain.Value {
+			t.Errorf("%s call with a dividend = %v, want less than %v", tr.name, call.Value, plain.Value)
+		}
+		if err := tr.price(&american, Call, American, 100.0, 100.0, 0.5, 0.25, 0.06, 0.06, dl, 200); err != nil {
+			t.Fatal(err)
+		}
+		if american.Value < call.Value {
+			t.Errorf("%s American call = %v, want at least the European %v", tr.name, american.Value, call.Value)
+		}
+		// A dividend that goes ex after the expiry is ignored.
+		var late ModelOutputs
+		if err := tr.price(&late, Call, European, 100.0, 100.0, 0.5, 0.25, 0.06, 0.06, DivList{}.AddDiv(0.75, 2.0), 200); err != nil {
+			t.Fatal(err)
+		}
+		if late.Value != plain.Value {
+			t.Errorf("%s call with a dividend after expiry = %v, want %v", tr.name, late.Value, plain.Value)
+		}
+	}
+}
+
+func TestTreesInvalidInputs(t *testing.T) {
+	cases := []struct {
+		name          string
+		s, k, t, v, r float64
+		field         string
+	}{
+		{"negative spot", -100.0, 100.0, 0.5, 0.2, 0.05, "s"},
+		{"zero spot", 0.0, 100.0, 0.5, 0.2, 0.05, "s"},
+		{"negative time", 100.0, 100.0, -0.5, 0.2, 0.05, "t"},
+		{"negative volatility", 100.0, 100.0, 0.5, -0.2, 0.05, "v"},
+		{"NaN rate", 100.0, 100.0, 0.5, 0.2, NaN(), "r"},
+		{"infinite rate", 100.0, 100.0, 0.5, 0.2, Inf(1), "r"},
+	}
+	for _, tr := range trees {
+		for _, c := range cases {
+			var out ModelOutputs
+			err := tr.price(&out, Put, American, c.s, c.k, c.t, c.v, c.r, c.r, nil, 50)
+			var e *analytical.InputError
+			if !errors.As(err, &e) || e.Pricer != tr.name || e.Field != c.field {
+				t.Errorf("%s with a %s returned %v, want an *analytical.InputError for %s", tr.name, c.name, err, c.field)
+			}
+		}
+		var out ModelOutputs
+		err := tr.price(&out, Put, ExerciseStyle(42), 100.0, 100.0, 0.5, 0.2, 0.05, 0.05, nil, 50)
+		if _, ok := err.(ErrUnsupportedExercise); !ok {
+			t.Errorf("%s with an invalid exercise style returned %v, want ErrUnsupportedExercise", tr.name, err)
+		}
+	}
+}
+
+func TestCRR1979ProbabilitiesOutOfRange(t *testing.T) {
+	// With so low a volatility, the up probability of the two-step tree
+	// exceeds 1.
+	var out ModelOutputs
+	err := out.CRR1979(Vanilla{Type: Call, Strike: 100.0}, European, 100.0, 1.0, 0.01, 0.5, 0.5, nil, 2)
+	var e *analytical.InputError
+	if !errors.As(err, &e) || e.Pricer != "CRR1979" || e.Field != "v" || !errors.Is(err, analytical.ErrInvalidInput) {
+		t.Errorf("CRR1979 returned %v, want an *analytical.InputError for v", err)
+	}
+	if e != nil && e.Inputs["n"] != 2.0 {
+		t.Errorf("InputError.Inputs = %v, want the number of time steps 2", e.Inputs)
+	}
+}
@@ -0,0 +1,225 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+/*
+Package lattice provides the lattice (tree) pricers that can be used to
+value financial instruments and their risks.
+
+This is a multi-file package and is made up of the following source files:
+  lattice.go    provides the common definitions that are used by the other
+                source files in the package;
+  binomial.go   provides the binomial tree pricers;
+  trinomial.go  provides the trinomial tree pricers.
+
+The trees are built on the spot price of the underlying instrument, which
+drops by the amount of a discrete cash dividend at the time step nearest to
+its ex-dividend time. The option values at that time step are carried over
+the drop by interpolating between the nodes, so the trees still recombine.
+*/
+package lattice
+
+import (
+	"fmt"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	. "math"
+	"sort"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidSteps is returned when the number of time steps of a
+tree is too small.
+*/
+type ErrInvalidSteps string
+
+func (e ErrInvalidSteps) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+The error ErrUnsupportedExercise is returned when a tree cannot value an
+option of the given exercise style.
+*/
+type ErrUnsupportedExercise string
+
+func (e ErrUnsupportedExercise) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+==================
+Common Definitions
+==================
+*/
+
+/*
+ModelOutputs is the structure that holds the results returned by the pricing
+methods defined in the package. The greeks are read off the nodes of the
+tree; Theta is scaled by the same market conventions as in the analytical
+package.
+*/
+type ModelOutputs struct {
+	Value float64
+	Delta float64
+	Gamma float64
+	Theta float64
+
+	Conventions *analytical.Conventions // scaling of Theta; analytical.DefaultConventions() if nil
+}
+
+/*
+checkContract is an unexported function that returns the error
+ErrInvalidPayoff of the options package if the payoff is nil, the error
+returned by the Validate method of the payoff if it is invalid, or the
+error ErrUnsupportedExercise if the exercise style is neither European nor
+American; otherwise, it returns nil.
+*/
+func checkContract(p Payoff, es ExerciseStyle) error {
+	if p == nil {
+		return ErrInvalidPayoff("The payoff is nil.")
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if es != European && es != American {
+		return ErrUnsupportedExercise("The trees can only value European and American options.")
+	}
+	return nil
+}
+
+/*
+checkInputs is an unexported function that returns an
+*analytical.InputError, with the name of the pricing method and its
+arguments in, if the spot price s is not positive, the time to expiry t or
+the volatility v is negative, or any of s, t, v, the risk-free rate r and
+the cost of carry b is not a finite number; otherwise, it returns nil.
+*/
+func checkInputs(pricer string, in map[string]float64) error {
+	for _, name := range []string{"s", "t", "v", "r", "b"} {
+		x := in[name]
+		reason := ""
+		switch {
+		case IsNaN(x) || IsInf(x, 0):
+			reason = "must be a finite number"
+		case name == "s" && x <= 0.0:
+			reason = "must be positive"
+		case (name == "t" || name == "v") && x < 0.0:
+			reason = "must not be negative"
+		}
+		if reason != "" {
+			return &analytical.InputError{Pricer: pricer, Field: name, Value: x, Reason: reason, Inputs: in}
+		}
+	}
+	return nil
+}
+
+/*
+divSteps is an unexported function that returns the total amount of the
+discrete dividends in the list that go ex at each of the n+1 time steps of
+a tree with the expiry t. A dividend goes ex at the time step nearest to
+its ex-dividend time, but not before the first one; dividends that go ex
+at or before the valuation time, or after the expiry, are ignored.
+*/
+func divSteps(t float64, n int, dl DivList) []float64 {
+	divs := make([]float64, n+1)
+	for _, v := range dl {
+		if tte, d := DestructDiv(v); tte > 0.0 && tte <= t {
+			i := int(Max(Floor(tte/t*float64(n)+0.5), 1.0))
+			divs[i] += d
+		}
+	}
+	return divs
+}
+
+/*
+exDividend is an unexported function that carries the option values at the
+nodes of a time step, with the given spot prices in increasing order,
+over the dividend div. On entry, the values are those just after the
+dividend goes ex; on return, they are those just before, i.e. the value at
+the spot price S becomes the value at S - div, which is interpolated by
+the quadratic through the three nearest nodes (or the line through the two
+nodes, if there are only two).
+*/
+func exDividend(values []float64, spots []float64, div float64) {
+	ex := append([]float64{}, values...)
+	m := len(spots)
+	for j, sp := range spots {
+		x := Max(sp-div, 0.0)
+		// The nodes k-1 and k bracket x, unless x lies beyond the outer nodes.
+		k := sort.SearchFloat64s(spots, x)
+		if k < 1 {
+			k = 1
+		} else if k > m-1 {
+			k = m - 1
+		}
+		if m == 2 {
+			w := (x - spots[0]) / (spots[1] - spots[0])
+			values[j] = ex[0] + w*(ex[1]-ex[0])
+			continue
+		}
+		// Take the third node on the side nearer to x.
+		i0 := k - 1
+		if k == m-1 || (i0 > 0 && x-spots[i0] < spots[k]-x) {
+			i0--
+		}
+		x0, x1, x2 := spots[i0], spots[i0+1], spots[i0+2]
+		values[j] = ex[i0]*(x-x1)*(x-x2)/((x0-x1)*(x0-x2)) +
+			ex[i0+1]*(x-x0)*(x-x2)/((x1-x0)*(x1-x2)) +
+			ex[i0+2]*(x-x0)*(x-x1)/((x2-x0)*(x2-x1))
+	}
+}
+
+/*
+check is an unexported method that returns an *analytical.OutputError, with
+the name of the pricing method and its arguments in, for the first of the
+fields of the ModelOutputs receiver that is not a finite number; otherwise,
+it scales Theta by the market conventions of the receiver and returns the
+error returned by analytical.Conventions.Scale.
+*/
+func (out *ModelOutputs) check(pricer string, in map[string]float64) error {
+	for _, f := range []struct {
+		name  string
+		value float64
+	}{{"Value", out.Value}, {"Delta", out.Delta}, {"Gamma", out.Gamma}, {"Theta", out.Theta}} {
+		if IsNaN(f.value) || IsInf(f.value, 0) {
+			return &analytical.OutputError{Pricer: pricer, Field: f.name, Value: f.value, Inputs: in}
+		}
+	}
+	// Scaling Theta based on market conventions.
+	_, theta, _, err := out.Conventions.Scale(0.0, out.Theta, 0.0)
+	if err != nil {
+		return err
+	}
+	out.Theta = theta
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package lattice
+
+import (
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	. "math"
+)
+
+/*
+========================================================================
+Provides the trinomial tree pricers for valuing European and American
+financial options.
+========================================================================
+*/
+
+/*
+--------------------------------------------------------------------------
+B1986 -- Boyle (1986) trinomial tree
+
+Description:
+A method that computes the theoretical value and greeks of a European or
+American option on an underlying instrument that may pay discrete cash
+dividends, and saves the computed results in the fields of the
+ModelOutputs receiver. It returns the error ErrInvalidSteps if n is less
+than 1, the error ErrInvalidPayoff of the options package if p is nil,
+the error returned by the Validate method of the payoff if it is
+invalid, the error ErrUnsupportedExercise if es is neither European nor
+American, an *analytical.InputError if an input is out of its valid
+range or v is too small for the tree probabilities to lie between 0 and
+1, an *analytical.OutputError if the value or a greek is not a finite
+number, or the error returned by analytical.Conventions.Scale if the
+conventions are invalid; otherwise, it returns nil.
+
+Usage:
+var out lattice.ModelOutputs
+err := out.B1986(p, es, s, t, v, r, b, dl, n)
+
+Arguments:
+p  payoff of the option (any type that implements the
+   options.Payoff interface in the options package)
+es exercise style (either options.European or
+   options.American from the options package)
+s  spot price of the underlying instrument
+t  time to expiry of the option
+v  volatility of the underlying instrument
+r  risk-free rate
+b  cost of carry
+dl discrete dividend list (the equity.DivList type
+   in the equity package; nil if there is none)
+n  number of time steps
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) B1986(p Payoff, es ExerciseStyle, s float64, t float64, v float64, r float64, b float64, dl DivList, n int) error {
+	if n < 1 {
+		return ErrInvalidSteps("The tree needs at least 1 time step.")
+	}
+	if err := checkContract(p, es); err != nil {
+		return err
+	}
+	in := map[string]float64{"s": s, "t": t, "v": v, "r": r, "b": b, "n": float64(n)}
+	if err := checkInputs("B1986", in); err != nil {
+		return err
+	}
+	dt := t / float64(n)
+	df := Exp((-r) * dt)
+	u := Exp(v * Sqrt(2.0*dt))
+	eu, ed, eb := Exp(v*Sqrt(dt/2.0)), Exp(-v*Sqrt(dt/2.0)), Exp(b*dt/2.0)
+	pu := ((eb - ed) / (eu - ed)) * ((eb - ed) / (eu - ed))
+	pd := ((eu - eb) / (eu - ed)) * ((eu - eb) / (eu - ed))
+	pm := 1.0 - pu - pd
+	if IsNaN(pm) || pu < 0.0 || pd < 0.0 || pm < 0.0 {
+		return &analytical.InputError{Pricer: "B1986", Field: "v", Value: v, Reason: "must be large enough for the tree probabilities to lie between 0 and 1", Inputs: in}
+	}
+	divs := divSteps(t, n, dl)
+	// Node j of time step i lies j-i up moves from the centre of the tree.
+	spot := func(i, j int) float64 {
+		return s * Pow(u, float64(j-i))
+	}
+	spots := make([]float64, 2*n+1)
+	values := make([]float64, 2*n+1)
+	for j := 0; j <= 2*n; j++ {
+		values[j] = p.Terminal(Max(spot(n, j)-divs[n], 0.0))
+	}
+	var step1 [3]float64
+	for i := n - 1; i >= 0; i-- {
+		for j := 0; j <= 2*i; j++ {
+			spots[j] = spot(i, j)
+			values[j] = df * (pu*values[j+2] + pm*values[j+1] + pd*values[j])
+			if es == American {
+				values[j] = Max(values[j], p.Terminal(spots[j]))
+			}
+		}
+		// Carry the values over a dividend that goes ex at this time step; the
+		// option may also be exercised just before it.
+		if divs[i] > 0.0 {
+			exDividend(values[:2*i+1], spots[:2*i+1], divs[i])
+			if es == American {
+				for j := 0; j <= 2*i; j++ {
+					values[j] = Max(values[j], p.Terminal(spots[j]))
+				}
+			}
+		}
+		// Keep the node values of the first time step for the greeks.
+		if i == 1 {
+			copy(step1[:], values[:3])
+		}
+	}
+	out.Value = values[0]
+	if n == 1 {
+		// The first time step is the expiry.
+		for j := range step1 {
+			step1[j] = p.Terminal(Max(spot(1, j)-divs[1], 0.0))
+		}
+	}
+	out.Delta = (step1[2] - step1[0]) / (spot(1, 2) - spot(1, 0))
+	out.Gamma = ((step1[2]-step1[1])/(spot(1, 2)-spot(1, 1)) -
+		(step1[1]-step1[0])/(spot(1, 1)-spot(1, 0))) /
+		(0.5 * (spot(1, 2) - spot(1, 0)))
+	// The middle node of the first time step sits at the spot price.
+	out.Theta = (step1[1] - values[0]) / dt
+	return out.check("B1986", in)
+}
@@ -0,0 +1,217 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package montecarlo
+
+import (
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	. "math"
+	"math/rand"
+)
+
+/*
+========================================================================
+Provides the Monte Carlo pricer for financial options on an underlying
+instrument whose spot price follows a geometric Brownian motion.
+========================================================================
+*/
+
+/*
+Bump sizes used by the GBM method to compute the greeks.
+*/
+const (
+	bumpS = 1.0e-2      // relative to the spot price
+	bumpT = 1.0 / 365.0 // in years
+	bumpV = 1.0e-2      // in units of volatility
+	bumpR = 1.0e-4      // in units of rate
+)
+
+/*
+--------------------------------------------------------------------------
+GBM -- Monte Carlo simulation of a geometric Brownian motion
+
+Description:
+A method that computes the theoretical value, its standard error and
+(optionally) the greeks of a financial option with an arbitrary, possibly
+path-dependent, payoff by simulating the spot price of the underlying
+instrument under the same dynamics as the GBSM method of the analytical
+package, and saves the computed results in the fields of the ModelOutputs
+receiver. The paths are simulated exactly (without discretisation error)
+at the time steps given in the settings, and the greeks are scaled by the
+market conventions of the receiver. It returns the error ErrInvalidConfig
+if the settings are invalid or the payoff is nil, the error returned by
+the Validate method of the payoff if it is invalid, an
+*analytical.InputError if an input is out of its valid range, an
+*analytical.OutputError if the value, a greek or the standard error is
+not a finite number, or the error returned by
+analytical.Conventions.Scale if the conventions are invalid; otherwise,
+it returns nil.
+
+Usage:
+var out montecarlo.ModelOutputs
+err := out.GBM(cfg, p, s, t, v, r, b)
+
+Arguments:
+cfg simulation settings (the montecarlo.Config type)
+p   payoff of the option (any type that implements the
+    options.Payoff interface in the options package)
+s   spot price of the underlying instrument
+t   time to expiry of the option
+v   volatility of the underlying instrument
+r   risk-free rate
+b   cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) GBM(cfg Config, p Payoff, s float64, t float64, v float64, r float64, b float64) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	if p == nil {
+		return ErrInvalidConfig("The payoff is nil.")
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	in := map[string]float64{"s": s, "t": t, "v": v, "r": r, "b": b}
+	if err := checkInputs("GBM", in); err != nil {
+		return err
+	}
+	var err error
+	out.Value, out.StdError, err = simulateGBM(cfg, p, s, t, v, r, b)
+	if err != nil {
+		return err
+	}
+	if cfg.Greeks {
+		// Every revaluation reuses the seed, so that the greeks are computed
+		// with common random numbers.
+		price := func(s, t, v, r, b float64) float64 {
+			value, _, e := simulateGBM(cfg, p, s, t, v, r, b)
+			if e != nil {
+				err = e
+			}
+			return value
+		}
+		ds := bumpS * s
+		up, down := price(s+ds, t, v, r, b), price(s-ds, t, v, r, b)
+		out.Delta = (up - down) / (2.0 * ds)
+		out.Gamma = (up - 2.0*out.Value + down) / (ds * ds)
+		out.Vega = (price(s, t, v+bumpV, r, b) - price(s, t, v-bumpV, r, b)) / (2.0 * bumpV)
+		if t > bumpT {
+			out.Theta = -(price(s, t+bumpT, v, r, b) - price(s, t-bumpT, v, r, b)) / (2.0 * bumpT)
+		} else {
+			out.Theta = -(price(s, t+bumpT, v, r, b) - out.Value) / bumpT
+		}
+		out.Rho = (price(s, t, v, r+bumpR, b+bumpR) - price(s, t, v, r-bumpR, b-bumpR)) / (2.0 * bumpR)
+		if err != nil {
+			return err
+		}
+		// Scaling some of the Greeks based on market conventions.
+		out.Vega, out.Theta, out.Rho, err = out.Conventions.Scale(out.Vega, out.Theta, out.Rho)
+		if err != nil {
+			return err
+		}
+	}
+	return out.check("GBM", in)
+}
+
+/*
+simulateGBM is an unexported function that returns the Monte Carlo estimate
+of the discounted payoff, and its standard error, under a geometric Brownian
+motion, applying the antithetic and control variates given in the settings.
+*/
+func simulateGBM(cfg Config, p Payoff, s float64, t float64, v float64, r float64, b float64) (float64, float64, error) {
+	// The exact value of the control variate is given by the GBSM method.
+	control := 0.0
+	if cfg.Control != nil {
+		var cv analytical.ModelOutputs
+		if err := cv.GBSM(cfg.Control.Type, s, cfg.Control.Strike, t, v, r, b); err != nil {
+			return NaN(), NaN(), err
+		}
+		control = cv.Value
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	dt := t / float64(cfg.Steps)
+	drift, diffusion := (b-v*v/2.0)*dt, v*Sqrt(dt)
+	disc := Exp((-r) * t)
+	z := make([]float64, cfg.Steps)
+	path := make([]float64, cfg.Steps+1)
+	xs := make([]float64, cfg.Paths)
+	cs := make([]float64, cfg.Paths)
+	for i := 0; i < cfg.Paths; i++ {
+		for j := range z {
+			z[j] = rng.NormFloat64()
+		}
+		x, c := evaluatePath(cfg, p, path, z, 1.0, s, drift, diffusion)
+		if cfg.Antithetic {
+			xa, ca := evaluatePath(cfg, p, path, z, -1.0, s, drift, diffusion)
+			x, c = (x+xa)/2.0, (c+ca)/2.0
+		}
+		xs[i], cs[i] = disc*x, disc*c
+	}
+	n := float64(cfg.Paths)
+	mx, mc := mean(xs), mean(cs)
+	vx, vc, cov := 0.0, 0.0, 0.0
+	for i := range xs {
+		vx += (xs[i] - mx) * (xs[i] - mx)
+		vc += (cs[i] - mc) * (cs[i] - mc)
+		cov += (xs[i] - mx) * (cs[i] - mc)
+	}
+	if cfg.Control == nil || vc == 0.0 {
+		return mx, Sqrt(vx / (n - 1.0) / n), nil
+	}
+	// Adjust the estimate by the optimal multiple of the control's error.
+	beta := cov / vc
+	return mx - beta*(mc-control), Sqrt(Max(vx-beta*cov, 0.0) / (n - 1.0) / n), nil
+}
+
+/*
+evaluatePath is an unexported function that fills the path of the spot price
+from the normal variates z (negated when sign is -1), and returns the payoff
+and the control variate's payoff along it.
+*/
+func evaluatePath(cfg Config, p Payoff, path []float64, z []float64, sign float64, s float64, drift float64, diffusion float64) (float64, float64) {
+	path[0] = s
+	for j := range z {
+		path[j+1] = path[j] * Exp(drift+sign*diffusion*z[j])
+	}
+	x, c := p.Path(path), 0.0
+	if cfg.Control != nil {
+		c = cfg.Control.Path(path)
+	}
+	return x, c
+}
+
+/*
+mean is an unexported function that returns the arithmetic mean of xs.
+*/
+func mean(xs []float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
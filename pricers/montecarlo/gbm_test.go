@@ -0,0 +1,122 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package montecarlo
+
+import (
+	"errors"
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	. "math"
+	"testing"
+)
+
+func TestGBMConvergesToGBSM(t *testing.T) {
+	cases := []struct {
+		ot OptionType
+		k  float64
+	}{
+		{Call, 90.0}, {Call, 100.0}, {Call, 110.0},
+		{Put, 90.0}, {Put, 100.0}, {Put, 110.0},
+	}
+	cfg := Config{Paths: 100000, Steps: 1, Seed: 42, Antithetic: true}
+	for _, c := range cases {
+		var exact analytical.ModelOutputs
+		if err := exact.GBSM(c.ot, 100.0, c.k, 0.5, 0.25, 0.05, 0.02); err != nil {
+			t.Fatal(err)
+		}
+		var out ModelOutputs
+		if err := out.GBM(cfg, Vanilla{Type: c.ot, Strike: c.k}, 100.0, 0.5, 0.25, 0.05, 0.02); err != nil {
+			t.Fatal(err)
+		}
+		// The estimate should lie within 4 standard errors of the exact value.
+		if Abs(out.Value-exact.Value) > 4.0*out.StdError {
+			t.Errorf("GBM(%v, k=%v) = %.4f +/- %.4f, want %.4f", c.ot, c.k, out.Value, out.StdError, exact.Value)
+		}
+	}
+}
+
+func TestGBMControlVariate(t *testing.T) {
+	p := Vanilla{Type: Call, Strike: 105.0}
+	plain := Config{Paths: 20000, Steps: 1, Seed: 7}
+	control := plain
+	control.Control = &p
+	var a, b ModelOutputs
+	if err := a.GBM(plain, p, 100.0, 1.0, 0.2, 0.03, 0.03); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.GBM(control, p, 100.0, 1.0, 0.2, 0.03, 0.03); err != nil {
+		t.Fatal(err)
+	}
+	// The payoff is its own control variate, so the estimate is exact.
+	var exact analytical.ModelOutputs
+	exact.GBSM(Call, 100.0, 105.0, 1.0, 0.2, 0.03, 0.03)
+	if Abs(b.Value-exact.Value) > 1.0e-8 || b.StdError > 1.0e-6*a.StdError {
+		t.Errorf("GBM with its payoff as control variate = %.10f +/- %.2e, want %.10f", b.Value, b.StdError, exact.Value)
+	}
+}
+
+func TestGBMDeterministic(t *testing.T) {
+	cfg := Config{Paths: 5000, Steps: 10, Seed: 3, Greeks: true}
+	var a, b ModelOutputs
+	a.GBM(cfg, Vanilla{Type: Put, Strike: 100.0}, 100.0, 1.0, 0.3, 0.05, 0.05)
+	b.GBM(cfg, Vanilla{Type: Put, Strike: 100.0}, 100.0, 1.0, 0.3, 0.05, 0.05)
+	if a != b {
+		t.Errorf("GBM with the same seed returned %+v and %+v", a, b)
+	}
+}
+
+func TestGBMInvalidInputs(t *testing.T) {
+	cases := []struct {
+		name       string
+		s, t, v, r float64
+		field      string
+	}{
+		{"negative spot", -100.0, 0.5, 0.2, 0.05, "s"},
+		{"zero spot", 0.0, 0.5, 0.2, 0.05, "s"},
+		{"negative time", 100.0, -0.5, 0.2, 0.05, "t"},
+		{"negative volatility", 100.0, 0.5, -0.2, 0.05, "v"},
+		{"infinite rate", 100.0, 0.5, 0.2, Inf(-1), "r"},
+	}
+	cfg := Config{Paths: 1000, Steps: 1, Seed: 1}
+	for _, c := range cases {
+		var out ModelOutputs
+		err := out.GBM(cfg, Vanilla{Type: Put, Strike: 100.0}, c.s, c.t, c.v, c.r, c.r)
+		var e *analytical.InputError
+		if !errors.As(err, &e) || e.Pricer != "GBM" || e.Field != c.field {
+			t.Errorf("GBM with a %s returned %v, want an *analytical.InputError for %s", c.name, err, c.field)
+		}
+	}
+}
+
+func TestGBMNonFinite(t *testing.T) {
+	var out ModelOutputs
+	err := out.GBM(Config{Paths: 100, Steps: 1, Seed: 42}, Vanilla{Type: Call, Strike: 100.0}, NaN(), 0.5, 0.25, 0.05, 0.02)
+	var e *analytical.InputError
+	if !errors.As(err, &e) || e.Pricer != "GBM" || e.Field != "s" || !errors.Is(err, analytical.ErrInvalidInput) {
+		t.Errorf("GBM with a NaN spot price returned %v, want an *analytical.InputError for s", err)
+	}
+}
@@ -0,0 +1,398 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package montecarlo
+
+import (
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/math"
+	. "github.com/kervinlow/quantstruct/options"
+	. "math"
+	"math/rand"
+	"sort"
+)
+
+/*
+========================================================================
+Provides the least-squares Monte Carlo pricer for financial options with
+early exercise.
+========================================================================
+*/
+
+/*
+BasisFunction represents a basis function of the regression that
+estimates the continuation value of an option; it is evaluated at the
+spot price divided by the initial spot price.
+*/
+type BasisFunction func(x float64) float64
+
+/*
+ExerciseBoundary is the structure that holds the diagnostics of the
+estimated exercise boundary at an exercise time: the lowest and highest
+spot prices at which the option is exercised (NaN if the option is never
+exercised at that time), which give the critical spot price of a call and
+a put respectively, and the fraction of the simulated paths that are
+exercised at that time.
+*/
+type ExerciseBoundary struct {
+	Time     float64
+	Lower    float64
+	Upper    float64
+	Fraction float64
+}
+
+/*
+MonomialBasis returns the n+1 monomials 1, x, x^2, ..., x^n as basis
+functions.
+
+Usage (example):
+var basis = montecarlo.MonomialBasis(3)
+*/
+func MonomialBasis(n int) []BasisFunction {
+	basis := make([]BasisFunction, n+1)
+	for i := range basis {
+		degree := float64(i)
+		basis[i] = func(x float64) float64 { return Pow(x, degree) }
+	}
+	return basis
+}
+
+/*
+LaguerreBasis returns the constant function and the first n weighted
+Laguerre polynomials exp(-x/2) L_i(x), i = 0, ..., n-1, as basis functions,
+as in Longstaff and Schwartz (2001).
+
+Usage (example):
+var basis = montecarlo.LaguerreBasis(3)
+*/
+func LaguerreBasis(n int) []BasisFunction {
+	basis := make([]BasisFunction, n+1)
+	basis[0] = func(x float64) float64 { return 1.0 }
+	for i := 1; i <= n; i++ {
+		degree := i - 1
+		basis[i] = func(x float64) float64 {
+			// Evaluate L_degree(x) by the three-term recurrence.
+			l0, l1 := 1.0, 1.0-x
+			if degree == 0 {
+				return Exp(-x/2.0) * l0
+			}
+			for k := 1; k < degree; k++ {
+				l0, l1 = l1, ((2.0*float64(k)+1.0-x)*l1-float64(k)*l0)/(float64(k)+1.0)
+			}
+			return Exp(-x/2.0) * l1
+		}
+	}
+	return basis
+}
+
+/*
+--------------------------------------------------------------------------
+LSM -- Longstaff and Schwartz (2001) least-squares Monte Carlo
+
+Description:
+A method that computes a lower-bound estimate of the theoretical value of
+a European, American or Bermudan option on an underlying instrument that
+follows a geometric Brownian motion and may pay discrete cash dividends,
+whose exercise value is given by its payoff at the spot price,
+together with its standard error, and saves the computed results in the
+Value and StdError fields of the ModelOutputs receiver; the greeks are not
+computed. The exercise rule is estimated by regressing the continuation
+value on the basis functions over one set of paths, and the option is then
+valued by applying that rule to an independent set of paths, so that the
+estimate is biased low. American options may be exercised at every time
+step of the simulation and Bermudan options at the exercise schedule and
+at expiry. Dividends are paid at their ex-dates, which are added to the
+time steps, and exercise at an ex-date happens before the dividend is
+paid. The control variate in the settings is not used. It returns the
+estimated exercise boundary at every exercise time before expiry. It
+returns the error ErrInvalidConfig if the settings, the exercise style or
+the exercise schedule are invalid (a Bermudan option needs a non-empty
+schedule) or the payoff is nil, the error returned by the Validate method
+of the payoff if it is invalid, an *analytical.InputError if an input is
+out of its valid range, or an *analytical.OutputError if the value or the
+standard error is not a finite number; otherwise, it returns nil.
+
+Usage:
+var out montecarlo.ModelOutputs
+eb, err := out.LSM(cfg, basis, p, es, sched, s, t, v, r, b, dl)
+
+Arguments:
+cfg   simulation settings (the montecarlo.Config type)
+basis basis functions of the regression (nil for
+      montecarlo.LaguerreBasis(3))
+p     payoff of the option (any type that implements the
+      options.Payoff interface in the options package)
+es    exercise style (options.European, options.American or
+      options.Bermudan from the options package)
+sched exercise times of a Bermudan option (nil otherwise)
+s     spot price of the underlying instrument
+t     time to expiry of the option
+v     volatility of the underlying instrument
+r     risk-free rate
+b     cost of carry
+dl    discrete dividend list (the equity.DivList type
+      in the equity package; nil if there is none)
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) LSM(cfg Config, basis []BasisFunction, p Payoff, es ExerciseStyle, sched []float64, s float64, t float64, v float64, r float64, b float64, dl DivList) ([]ExerciseBoundary, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	switch es {
+	case European, American:
+	case Bermudan:
+		if len(sched) == 0 {
+			return nil, ErrInvalidConfig("A Bermudan option needs an exercise schedule.")
+		}
+	default:
+		return nil, ErrInvalidConfig("The exercise style is invalid.")
+	}
+	in := map[string]float64{"s": s, "t": t, "v": v, "r": r, "b": b}
+	if err := checkInputs("LSM", in); err != nil {
+		return nil, err
+	}
+	if basis == nil {
+		basis = LaguerreBasis(3)
+	}
+	for _, e := range sched {
+		if es == Bermudan && (e <= 0.0 || e > t) {
+			return nil, ErrInvalidConfig("The exercise schedule lies outside the life of the option.")
+		}
+	}
+	grid, divs := lsmGrid(cfg.Steps, sched, t, dl)
+	// exercisable marks the time steps before expiry at which the option
+	// may be exercised.
+	exercisable := make([]bool, len(grid))
+	for i := 1; i < len(grid)-1; i++ {
+		switch es {
+		case American:
+			exercisable[i] = true
+		case Bermudan:
+			for _, e := range sched {
+				exercisable[i] = exercisable[i] || e == grid[i]
+			}
+		}
+	}
+	if p == nil {
+		return nil, ErrInvalidConfig("The payoff is nil.")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	coefs := lsmRegress(cfg, basis, p.Terminal, exercisable, grid, divs, s, v, r, b)
+	// Apply the estimated exercise rule to an independent set of paths.
+	rng := rand.New(rand.NewSource(cfg.Seed + 1))
+	z := make([]float64, len(grid)-1)
+	path := make([]float64, len(grid))
+	xs := make([]float64, cfg.Paths)
+	counts := make([]int, len(grid))
+	lower, upper := make([]float64, len(grid)), make([]float64, len(grid))
+	for i := range grid {
+		lower[i], upper[i] = NaN(), NaN()
+	}
+	signs := []float64{1.0}
+	if cfg.Antithetic {
+		signs = append(signs, -1.0)
+	}
+	sims := float64(len(signs))
+	for n := range xs {
+		for j := range z {
+			z[j] = rng.NormFloat64()
+		}
+		for _, sign := range signs {
+			lsmPath(path, z, sign, grid, divs, s, v, b)
+			i := 1
+			for ; i < len(grid)-1; i++ {
+				x := p.Terminal(path[i])
+				if coefs[i] != nil && x > 0.0 && x >= continuation(coefs[i], basis, path[i]/s) {
+					counts[i]++
+					if IsNaN(lower[i]) || path[i] < lower[i] {
+						lower[i] = path[i]
+					}
+					if IsNaN(upper[i]) || path[i] > upper[i] {
+						upper[i] = path[i]
+					}
+					break
+				}
+			}
+			xs[n] += Exp((-r)*grid[i]) * p.Terminal(path[i]) / sims
+		}
+	}
+	n := float64(cfg.Paths)
+	out.Value = mean(xs)
+	variance := 0.0
+	for _, x := range xs {
+		variance += (x - out.Value) * (x - out.Value)
+	}
+	out.StdError = Sqrt(variance / (n - 1.0) / n)
+	out.Delta, out.Gamma, out.Vega, out.Theta, out.Rho = 0.0, 0.0, 0.0, 0.0, 0.0
+	boundary := make([]ExerciseBoundary, 0)
+	for i := range grid {
+		if exercisable[i] {
+			boundary = append(boundary, ExerciseBoundary{grid[i], lower[i], upper[i], float64(counts[i]) / (n * sims)})
+		}
+	}
+	return boundary, out.check("LSM", in)
+}
+
+/*
+lsmGrid is an unexported function that returns the simulation times, which
+are the equally spaced time steps merged with the exercise schedule and the
+ex-dates of the dividends before expiry, together with the dividend paid
+at each of those times.
+*/
+func lsmGrid(steps int, sched []float64, t float64, dl DivList) ([]float64, []float64) {
+	times := make([]float64, 0, steps+1+len(sched)+len(dl))
+	for i := 0; i <= steps; i++ {
+		times = append(times, t*float64(i)/float64(steps))
+	}
+	times = append(times, sched...)
+	for _, d := range dl {
+		if d.TimeToDividend > 0.0 && d.TimeToDividend < t {
+			times = append(times, d.TimeToDividend)
+		}
+	}
+	sort.Float64s(times)
+	grid := make([]float64, 0, len(times))
+	for _, x := range times {
+		if len(grid) == 0 || x > grid[len(grid)-1] {
+			grid = append(grid, x)
+		}
+	}
+	divs := make([]float64, len(grid))
+	for _, d := range dl {
+		for i := range grid {
+			if grid[i] == d.TimeToDividend && i < len(grid)-1 {
+				divs[i] += d.Amount
+			}
+		}
+	}
+	return grid, divs
+}
+
+/*
+lsmPath is an unexported function that fills the path of the spot price at
+the simulation times from the normal variates z (negated when sign is -1).
+The dividend paid at a time is deducted from the spot price right after
+that time.
+*/
+func lsmPath(path []float64, z []float64, sign float64, grid []float64, divs []float64, s float64, v float64, b float64) {
+	path[0] = s
+	for j := range z {
+		dt := grid[j+1] - grid[j]
+		path[j+1] = Max(path[j]-divs[j], 0.0) * Exp((b-v*v/2.0)*dt+sign*v*Sqrt(dt)*z[j])
+	}
+}
+
+/*
+lsmRegress is an unexported function that simulates the regression paths
+and returns, for every exercisable time, the regression coefficients of the
+continuation value on the basis functions (nil where there are too few
+in-the-money paths to regress on).
+*/
+func lsmRegress(cfg Config, basis []BasisFunction, exercise func(float64) float64, exercisable []bool, grid []float64, divs []float64, s float64, v float64, r float64, b float64) [][]float64 {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	paths := make([][]float64, 0, 2*cfg.Paths)
+	z := make([]float64, len(grid)-1)
+	for p := 0; p < cfg.Paths; p++ {
+		for j := range z {
+			z[j] = rng.NormFloat64()
+		}
+		path := make([]float64, len(grid))
+		lsmPath(path, z, 1.0, grid, divs, s, v, b)
+		paths = append(paths, path)
+		if cfg.Antithetic {
+			path = make([]float64, len(grid))
+			lsmPath(path, z, -1.0, grid, divs, s, v, b)
+			paths = append(paths, path)
+		}
+	}
+	// Work backwards from expiry, keeping the cash flow of every path and
+	// the time at which it is paid.
+	last := len(grid) - 1
+	cash := make([]float64, len(paths))
+	when := make([]float64, len(paths))
+	for p, path := range paths {
+		cash[p], when[p] = exercise(path[last]), grid[last]
+	}
+	coefs := make([][]float64, len(grid))
+	m := len(basis)
+	for i := last - 1; i > 0; i-- {
+		if !exercisable[i] {
+			continue
+		}
+		// Regress the discounted cash flows of the in-the-money paths.
+		ata := make([][]float64, m)
+		for j := range ata {
+			ata[j] = make([]float64, m)
+		}
+		aty := make([]float64, m)
+		f := make([]float64, m)
+		itm := 0
+		for p, path := range paths {
+			if exercise(path[i]) <= 0.0 {
+				continue
+			}
+			itm++
+			y := Exp((-r)*(when[p]-grid[i])) * cash[p]
+			for j := range basis {
+				f[j] = basis[j](path[i] / s)
+			}
+			for j := range f {
+				aty[j] += f[j] * y
+				for l := range f {
+					ata[j][l] += f[j] * f[l]
+				}
+			}
+		}
+		if itm <= m {
+			continue
+		}
+		beta, err := SolveLinear(ata, aty)
+		if err != nil {
+			continue
+		}
+		coefs[i] = beta
+		for p, path := range paths {
+			if x := exercise(path[i]); x > 0.0 && x >= continuation(beta, basis, path[i]/s) {
+				cash[p], when[p] = x, grid[i]
+			}
+		}
+	}
+	return coefs
+}
+
+/*
+continuation is an unexported function that returns the regression estimate
+of the continuation value at the moneyness x.
+*/
+func continuation(beta []float64, basis []BasisFunction, x float64) float64 {
+	sum := 0.0
+	for j := range basis {
+		sum += beta[j] * basis[j](x)
+	}
+	return sum
+}
@@ -0,0 +1,181 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package montecarlo
+
+import (
+	"errors"
+	. "github.com/kervinlow/quantstruct/equity"
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	"github.com/kervinlow/quantstruct/pricers/lattice"
+	. "math"
+	"testing"
+)
+
+func TestLSMAmericanPut(t *testing.T) {
+	cfg := Config{Paths: 50000, Steps: 50, Seed: 11, Antithetic: true}
+	for _, k := range []float64{90.0, 100.0, 110.0} {
+		p := Vanilla{Type: Put, Strike: k}
+		var tree lattice.ModelOutputs
+		if err := tree.CRR1979(p, American, 100.0, 1.0, 0.25, 0.06, 0.06, nil, 2000); err != nil {
+			t.Fatal(err)
+		}
+		var out ModelOutputs
+		if _, err := out.LSM(cfg, nil, p, American, nil, 100.0, 1.0, 0.25, 0.06, 0.06, nil); err != nil {
+			t.Fatal(err)
+		}
+		// The estimate is biased low by the suboptimal exercise rule and by the
+		// exercise dates being discrete, so allow a small bias below the tree.
+		if out.Value > tree.Value+3.0*out.StdError || out.Value < tree.Value-0.05-3.0*out.StdError {
+			t.Errorf("LSM(American put, k=%v) = %.4f +/- %.4f, want about %.4f", k, out.Value, out.StdError, tree.Value)
+		}
+	}
+}
+
+func TestLSMEuropean(t *testing.T) {
+	cfg := Config{Paths: 50000, Steps: 20, Seed: 5, Antithetic: true}
+	p := Vanilla{Type: Put, Strike: 100.0}
+	var tree lattice.ModelOutputs
+	tree.CRR1979(p, European, 100.0, 1.0, 0.25, 0.06, 0.06, nil, 2000)
+	var out ModelOutputs
+	if _, err := out.LSM(cfg, nil, p, European, nil, 100.0, 1.0, 0.25, 0.06, 0.06, nil); err != nil {
+		t.Fatal(err)
+	}
+	if Abs(out.Value-tree.Value) > 4.0*out.StdError+0.01 {
+		t.Errorf("LSM(European put) = %.4f +/- %.4f, want %.4f", out.Value, out.StdError, tree.Value)
+	}
+}
+
+func TestLSMBermudan(t *testing.T) {
+	cfg := Config{Paths: 50000, Steps: 20, Seed: 13, Antithetic: true}
+	var european, american lattice.ModelOutputs
+	european.CRR1979(Vanilla{Type: Put, Strike: 105.0}, European, 100.0, 1.0, 0.25, 0.06, 0.06, nil, 2000)
+	american.CRR1979(Vanilla{Type: Put, Strike: 105.0}, American, 100.0, 1.0, 0.25, 0.06, 0.06, nil, 2000)
+	sched := []float64{0.25, 0.5, 0.75}
+	var out ModelOutputs
+	eb, err := out.LSM(cfg, nil, Vanilla{Type: Put, Strike: 105.0}, Bermudan, sched, 100.0, 1.0, 0.25, 0.06, 0.06, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A Bermudan option is worth more than the European option, and less
+	// than the American option.
+	if out.Value < european.Value+3.0*out.StdError || out.Value > american.Value+3.0*out.StdError {
+		t.Errorf("LSM(Bermudan put) = %.4f +/- %.4f, want between %.4f and %.4f", out.Value, out.StdError, european.Value, american.Value)
+	}
+	// The exercise boundary is reported at the exercise schedule only.
+	if len(eb) != len(sched) {
+		t.Fatalf("LSM(Bermudan put) returned %d exercise times, want %d", len(eb), len(sched))
+	}
+	for i, e := range eb {
+		if e.Time != sched[i] {
+			t.Errorf("exercise boundary %d at time %v, want %v", i, e.Time, sched[i])
+		}
+	}
+}
+
+func TestLSMExerciseBoundary(t *testing.T) {
+	cfg := Config{Paths: 20000, Steps: 10, Seed: 17, Antithetic: true}
+	var out ModelOutputs
+	eb, err := out.LSM(cfg, nil, Vanilla{Type: Put, Strike: 100.0}, American, nil, 100.0, 1.0, 0.25, 0.06, 0.06, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An American option may be exercised at every time step before expiry.
+	if len(eb) != cfg.Steps-1 {
+		t.Fatalf("LSM(American put) returned %d exercise times, want %d", len(eb), cfg.Steps-1)
+	}
+	total := 0.0
+	for i, e := range eb {
+		if Abs(e.Time-float64(i+1)/float64(cfg.Steps)) > 1.0e-12 {
+			t.Errorf("exercise boundary %d at time %v, want %v", i, e.Time, float64(i+1)/float64(cfg.Steps))
+		}
+		// The put is only exercised in the money.
+		if !IsNaN(e.Upper) && e.Upper >= 100.0 {
+			t.Errorf("critical spot price at time %v = %v, want below the strike", e.Time, e.Upper)
+		}
+		if e.Fraction < 0.0 || e.Fraction > 1.0 {
+			t.Errorf("exercised fraction at time %v = %v, want between 0 and 1", e.Time, e.Fraction)
+		}
+		total += e.Fraction
+	}
+	// The exercise boundary of a put rises towards the strike at expiry.
+	if last := eb[len(eb)-1]; IsNaN(last.Upper) || last.Upper < eb[0].Upper {
+		t.Errorf("critical spot price at time %v = %v, want at least %v", last.Time, last.Upper, eb[0].Upper)
+	}
+	if total <= 0.0 || total > 1.0 {
+		t.Errorf("total exercised fraction = %v, want between 0 and 1", total)
+	}
+}
+
+func TestLSMDividends(t *testing.T) {
+	// A large dividend just before expiry makes the early exercise of a call
+	// worthwhile.
+	dl := DivList{}.AddDiv(0.9, 5.0)
+	cfg := Config{Paths: 50000, Steps: 50, Seed: 19, Antithetic: true}
+	var tree, european lattice.ModelOutputs
+	if err := tree.CRR1979(Vanilla{Type: Call, Strike: 95.0}, American, 100.0, 1.0, 0.2, 0.05, 0.05, dl, 2000); err != nil {
+		t.Fatal(err)
+	}
+	european.CRR1979(Vanilla{Type: Call, Strike: 95.0}, European, 100.0, 1.0, 0.2, 0.05, 0.05, dl, 2000)
+	var out ModelOutputs
+	if _, err := out.LSM(cfg, nil, Vanilla{Type: Call, Strike: 95.0}, American, nil, 100.0, 1.0, 0.2, 0.05, 0.05, dl); err != nil {
+		t.Fatal(err)
+	}
+	if out.Value > tree.Value+3.0*out.StdError || out.Value < tree.Value-0.05-3.0*out.StdError {
+		t.Errorf("LSM(American call with a dividend) = %.4f +/- %.4f, want about %.4f", out.Value, out.StdError, tree.Value)
+	}
+	if out.Value < european.Value+0.1 {
+		t.Errorf("LSM(American call with a dividend) = %.4f, want well above the European %.4f", out.Value, european.Value)
+	}
+}
+
+func TestLSMInvalidExercise(t *testing.T) {
+	cfg := Config{Paths: 1000, Steps: 10, Seed: 1}
+	cases := []struct {
+		name  string
+		es    ExerciseStyle
+		sched []float64
+	}{
+		{"Bermudan with a nil schedule", Bermudan, nil},
+		{"Bermudan with an empty schedule", Bermudan, []float64{}},
+		{"Bermudan with a schedule after expiry", Bermudan, []float64{0.5, 1.5}},
+		{"invalid exercise style", ExerciseStyle(42), nil},
+	}
+	for _, c := range cases {
+		var out ModelOutputs
+		_, err := out.LSM(cfg, nil, Vanilla{Type: Put, Strike: 100.0}, c.es, c.sched, 100.0, 1.0, 0.25, 0.06, 0.06, nil)
+		if _, ok := err.(ErrInvalidConfig); !ok {
+			t.Errorf("LSM with a %s returned %v, want ErrInvalidConfig", c.name, err)
+		}
+	}
+	var out ModelOutputs
+	_, err := out.LSM(cfg, nil, Vanilla{Type: Put, Strike: 100.0}, American, nil, -100.0, 1.0, 0.25, 0.06, 0.06, nil)
+	var e *analytical.InputError
+	if !errors.As(err, &e) || e.Pricer != "LSM" || e.Field != "s" {
+		t.Errorf("LSM with a negative spot returned %v, want an *analytical.InputError for s", err)
+	}
+}
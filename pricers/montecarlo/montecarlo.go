@@ -0,0 +1,168 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+/*
+Package montecarlo provides the Monte Carlo pricers that can be used to
+value financial instruments and their risks.
+
+This is a multi-file package and is made up of the following source files:
+  montecarlo.go  provides the common definitions that are used by the other
+                 source files in the package;
+  gbm.go         provides the Monte Carlo pricer for underlying instruments
+                 that follow a geometric Brownian motion;
+  lsm.go         provides the least-squares Monte Carlo pricer for options
+                 with early exercise.
+*/
+package montecarlo
+
+import (
+	"fmt"
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	. "math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidConfig is returned when the simulation settings are
+invalid.
+*/
+type ErrInvalidConfig string
+
+func (e ErrInvalidConfig) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+==================
+Common Definitions
+==================
+*/
+
+/*
+ModelOutputs is the structure that holds the results returned by the pricing
+methods defined in the package. The greeks are scaled by the same market
+conventions as in the analytical package, and StdError is the standard
+error of the Monte Carlo estimate of Value.
+*/
+type ModelOutputs struct {
+	Value    float64
+	Delta    float64
+	Gamma    float64
+	Vega     float64
+	Theta    float64
+	Rho      float64
+	StdError float64
+
+	Conventions *analytical.Conventions // scaling of the greeks; analytical.DefaultConventions() if nil
+}
+
+/*
+Config is the structure that holds the settings of a simulation. The
+results are deterministic for a given Seed. When Antithetic is set, each
+of the Paths draws is used twice, with its normal variates negated the
+second time. When Control is not nil, the European option with the payoff
+it describes (an options.Vanilla from the options package) is used as a
+control variate, with its exact value given by the GBSM method of the
+analytical package. When Greeks is set, the greeks are computed by
+revaluation with common random numbers; otherwise, only Value and StdError
+are computed.
+
+Usage (example):
+var cfg = montecarlo.Config{Paths: 100000, Steps: 50, Seed: 1, Antithetic: true}
+*/
+type Config struct {
+	Paths      int
+	Steps      int
+	Seed       int64
+	Antithetic bool
+	Control    *Vanilla
+	Greeks     bool
+}
+
+/*
+validate is an unexported method that returns the error ErrInvalidConfig
+if the simulation settings are invalid; otherwise, it returns nil.
+*/
+func (cfg Config) validate() error {
+	if cfg.Paths < 2 {
+		return ErrInvalidConfig("The simulation needs at least 2 paths.")
+	}
+	if cfg.Steps < 1 {
+		return ErrInvalidConfig("The simulation needs at least 1 time step.")
+	}
+	return nil
+}
+
+/*
+checkInputs is an unexported function that returns an
+*analytical.InputError, with the name of the pricing method and its
+arguments in, if the spot price s is not positive, the time to expiry t or
+the volatility v is negative, or any of s, t, v, the risk-free rate r and
+the cost of carry b is not a finite number; otherwise, it returns nil.
+*/
+func checkInputs(pricer string, in map[string]float64) error {
+	for _, name := range []string{"s", "t", "v", "r", "b"} {
+		x := in[name]
+		reason := ""
+		switch {
+		case IsNaN(x) || IsInf(x, 0):
+			reason = "must be a finite number"
+		case name == "s" && x <= 0.0:
+			reason = "must be positive"
+		case (name == "t" || name == "v") && x < 0.0:
+			reason = "must not be negative"
+		}
+		if reason != "" {
+			return &analytical.InputError{Pricer: pricer, Field: name, Value: x, Reason: reason, Inputs: in}
+		}
+	}
+	return nil
+}
+
+/*
+check is an unexported method that returns an *analytical.OutputError, with
+the name of the pricing method and its arguments in, for the first of the
+fields of the ModelOutputs receiver that is not a finite number;
+otherwise, it returns nil.
+*/
+func (out *ModelOutputs) check(pricer string, in map[string]float64) error {
+	for _, f := range []struct {
+		name  string
+		value float64
+	}{{"Value", out.Value}, {"Delta", out.Delta}, {"Gamma", out.Gamma}, {"Vega", out.Vega},
+		{"Theta", out.Theta}, {"Rho", out.Rho}, {"StdError", out.StdError}} {
+		if IsNaN(f.value) || IsInf(f.value, 0) {
+			return &analytical.OutputError{Pricer: pricer, Field: f.name, Value: f.value, Inputs: in}
+		}
+	}
+	return nil
+}
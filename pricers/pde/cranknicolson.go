@@ -0,0 +1,357 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package pde
+
+import (
+	. "github.com/kervinlow/quantstruct/math"
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	. "math"
+)
+
+/*
+========================================================================
+Provides the Crank-Nicolson finite-difference pricers for the Generalized
+Black Scholes Merton partial differential equation.
+========================================================================
+*/
+
+/*
+--------------------------------------------------------------------------
+CN1947 -- Crank and Nicolson (1947) finite-difference scheme
+
+Description:
+A method that computes the theoretical value and greeks of a European or
+American option by solving the Generalized Black Scholes Merton partial
+differential equation with the Crank-Nicolson scheme and Rannacher
+smoothing on a uniform grid of spot prices, and saves the computed results
+in the fields of the ModelOutputs receiver. American exercise is handled
+by projected successive over-relaxation (PSOR). It returns the full grid of
+values. It returns the error ErrInvalidConfig if the settings are invalid,
+es is neither European nor American or the payoff is nil, the error
+returned by the Validate method of the payoff if it is invalid, an
+*analytical.InputError if an input is out of its valid range or
+cfg.MaxIterations is too small for PSOR to converge, an
+*analytical.OutputError if the value or a greek is not a finite number, or
+the error returned by analytical.Conventions.Scale if the conventions are
+invalid; otherwise, it returns nil.
+
+Usage:
+var out pde.ModelOutputs
+grid, err := out.CN1947(cfg, p, es, s, t, v, r, b)
+
+Arguments:
+cfg grid settings (the pde.Config type)
+p   payoff of the option (any type that implements the
+    options.Payoff interface in the options package)
+es  exercise style (either options.European or
+    options.American from the options package)
+s   spot price of the underlying instrument
+t   time to expiry of the option
+v   volatility of the underlying instrument
+r   risk-free rate
+b   cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) CN1947(cfg Config, p Payoff, es ExerciseStyle, s float64, t float64, v float64, r float64, b float64) (*Grid, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if es != European && es != American {
+		return nil, ErrInvalidConfig("Only European and American exercise are supported.")
+	}
+	if p == nil {
+		return nil, ErrInvalidConfig("The payoff is nil.")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	inputs := map[string]float64{"s": s, "t": t, "v": v, "r": r, "b": b}
+	if err := checkInputs("CN1947", inputs); err != nil {
+		return nil, err
+	}
+	spots := spotGrid(cfg, 0.0, cfg.SpotMax, s, t, v, b)
+	payoff := make([]float64, len(spots))
+	for i, x := range spots {
+		payoff[i] = p.Terminal(x)
+	}
+	var exercise []float64
+	if es == American {
+		exercise = payoff
+	}
+	// The value at the edges of the grid tends to the discounted payoff at
+	// the forward price (or to the payoff at the spot price, if higher, for
+	// an American option).
+	edge := func(x float64) func(float64) float64 {
+		return func(tau float64) float64 {
+			value := Exp((-r)*tau) * p.Terminal(x*Exp(b*tau))
+			if es == American {
+				value = Max(value, p.Terminal(x))
+			}
+			return value
+		}
+	}
+	grid, err := crankNicolson("CN1947", inputs, cfg, spots, t, v, r, b, payoff, edge(spots[0]), edge(spots[len(spots)-1]), exercise)
+	if err != nil {
+		return nil, err
+	}
+	return grid, out.greeks("CN1947", inputs, grid, s)
+}
+
+/*
+--------------------------------------------------------------------------
+CN1947Barrier -- Crank and Nicolson (1947) finite-difference scheme for
+single-barrier options
+
+Description:
+A method that computes the theoretical value and greeks of a European
+single-barrier option (down-and-in, up-and-in, down-and-out or up-and-out
+call or put) with a cash rebate by solving the Generalized Black Scholes
+Merton partial differential equation with the Crank-Nicolson scheme and
+Rannacher smoothing, and saves the computed results in the fields of the
+ModelOutputs receiver. The grid ends at the barrier. The rebate of a
+knock-in option is paid at expiry if the barrier was never touched, and
+the value of a knock-in option at the barrier is that of the European
+option with the same payoff given by the GBSMPayoff method of the
+analytical package; the rebate of a knock-out option is paid as soon as
+the barrier is touched. It returns the full grid of values. It returns the
+error ErrInvalidConfig if the settings or the barrier type are invalid or
+the spot price lies beyond the barrier or the payoff is nil, the error
+returned by the Validate method of the payoff if it is invalid, the error
+*analytical.InputError if an input is out of its valid range, the error
+returned by the GBSMPayoff method if it cannot value the payoff of a
+knock-in option, an *analytical.OutputError if the value or a greek is not
+a finite number, or the error returned by analytical.Conventions.Scale if
+the conventions are invalid; otherwise, it returns nil.
+
+Usage:
+var out pde.ModelOutputs
+grid, err := out.CN1947Barrier(cfg, p, bt, s, h, x, t, v, r, b)
+
+Arguments:
+cfg grid settings (the pde.Config type)
+p   payoff of the option (any type that implements the
+    options.Payoff interface in the options package)
+bt  barrier type (options.DownAndIn, options.UpAndIn,
+    options.DownAndOut or options.UpAndOut from the
+    options package)
+s   spot price of the underlying instrument
+h   barrier level
+x   cash rebate
+t   time to expiry of the option
+v   volatility of the underlying instrument
+r   risk-free rate
+b   cost of carry
+--------------------------------------------------------------------------
+*/
+func (out *ModelOutputs) CN1947Barrier(cfg Config, p Payoff, bt BarrierType, s float64, h float64, x float64, t float64, v float64, r float64, b float64) (*Grid, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrInvalidConfig("The payoff is nil.")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if bt != DownAndIn && bt != UpAndIn && bt != DownAndOut && bt != UpAndOut {
+		return nil, ErrInvalidConfig("The barrier type is invalid.")
+	}
+	inputs := map[string]float64{"s": s, "h": h, "x": x, "t": t, "v": v, "r": r, "b": b}
+	if err := checkInputs("CN1947Barrier", inputs); err != nil {
+		return nil, err
+	}
+	if !(h > 0.0) || IsInf(h, 1) {
+		return nil, &analytical.InputError{Pricer: "CN1947Barrier", Field: "h", Value: h, Reason: "must be a positive finite number", Inputs: inputs}
+	}
+	if IsNaN(x) || IsInf(x, 0) {
+		return nil, &analytical.InputError{Pricer: "CN1947Barrier", Field: "x", Value: x, Reason: "must be a finite number", Inputs: inputs}
+	}
+	down := bt == DownAndIn || bt == DownAndOut
+	in := bt == DownAndIn || bt == UpAndIn
+	if (down && s <= h) || (!down && s >= h) {
+		return nil, ErrInvalidConfig("The spot price lies beyond the barrier.")
+	}
+	var spots []float64
+	if down {
+		spots = spotGrid(cfg, h, cfg.SpotMax, s, t, v, b)
+	} else {
+		spots = spotGrid(cfg, 0.0, h, s, t, v, b)
+	}
+	// A knock-in option pays the rebate at expiry, and becomes the European
+	// option at the barrier; far from the barrier, it is never knocked in.
+	// A knock-out option pays the payoff at expiry, and the rebate at
+	// the barrier; far from the barrier, it is never knocked out.
+	payoff := make([]float64, len(spots))
+	for i, spot := range spots {
+		if in {
+			payoff[i] = x
+		} else {
+			payoff[i] = p.Terminal(spot)
+		}
+	}
+	var errBarrier error
+	barrier := func(tau float64) float64 {
+		if !in {
+			return x
+		}
+		if tau == 0.0 {
+			return p.Terminal(h)
+		}
+		var european analytical.ModelOutputs
+		if err := european.GBSMPayoff(p, h, tau, v, r, b); err != nil {
+			errBarrier = err
+		}
+		return european.Value
+	}
+	far := func(spot float64) func(float64) float64 {
+		return func(tau float64) float64 {
+			if in {
+				return x * Exp((-r)*tau)
+			}
+			return Exp((-r)*tau) * p.Terminal(spot*Exp(b*tau))
+		}
+	}
+	var grid *Grid
+	var err error
+	if down {
+		grid, err = crankNicolson("CN1947Barrier", inputs, cfg, spots, t, v, r, b, payoff, barrier, far(spots[len(spots)-1]), nil)
+	} else {
+		grid, err = crankNicolson("CN1947Barrier", inputs, cfg, spots, t, v, r, b, payoff, far(spots[0]), barrier, nil)
+	}
+	if errBarrier != nil {
+		return nil, errBarrier
+	}
+	if err != nil {
+		return nil, err
+	}
+	return grid, out.greeks("CN1947Barrier", inputs, grid, s)
+}
+
+/*
+spotGrid is an unexported function that returns a uniform grid of spot
+prices from lo to hi; when hi is zero, it is set to five standard
+deviations above the spot price.
+*/
+func spotGrid(cfg Config, lo float64, hi float64, s float64, t float64, v float64, b float64) []float64 {
+	if hi == 0.0 {
+		hi = s * Exp(Max(b, 0.0)*t+5.0*v*Sqrt(t))
+	}
+	spots := make([]float64, cfg.SpotSteps+1)
+	for i := range spots {
+		spots[i] = lo + (hi-lo)*float64(i)/float64(cfg.SpotSteps)
+	}
+	return spots
+}
+
+/*
+crankNicolson is an unexported function that solves the Generalized Black
+Scholes Merton partial differential equation backwards from expiry on the
+given spot prices, starting from the payoff, with the values at the lower
+and upper edges of the grid given as functions of the time to expiry. When
+exercise is not nil, the values are kept at or above it by PSOR. The errors
+that it returns carry the name of the pricing method and its arguments
+inputs.
+*/
+func crankNicolson(pricer string, inputs map[string]float64, cfg Config, spots []float64, t float64, v float64, r float64, b float64, payoff []float64, lower func(float64) float64, upper func(float64) float64, exercise []float64) (*Grid, error) {
+	n, m := cfg.TimeSteps, len(spots)-1
+	ds := spots[1] - spots[0]
+	dt := t / float64(n)
+	// Coefficients of the spatial operator at the interior nodes.
+	lo, di, up := make([]float64, m-1), make([]float64, m-1), make([]float64, m-1)
+	for i := 1; i < m; i++ {
+		diffusion := 0.5 * v * v * spots[i] * spots[i] / (ds * ds)
+		convection := b * spots[i] / (2.0 * ds)
+		lo[i-1], di[i-1], up[i-1] = diffusion-convection, -2.0*diffusion-r, diffusion+convection
+	}
+	// step advances the values u at time to expiry tau by dtau with the
+	// theta-scheme (theta = 1/2 for Crank-Nicolson, 1 for fully implicit).
+	step := func(u []float64, tau float64, dtau float64, theta float64) ([]float64, error) {
+		a, d, c, rhs := make([]float64, m-1), make([]float64, m-1), make([]float64, m-1), make([]float64, m-1)
+		for i := 1; i < m; i++ {
+			j := i - 1
+			a[j], d[j], c[j] = -theta*dtau*lo[j], 1.0-theta*dtau*di[j], -theta*dtau*up[j]
+			rhs[j] = u[i] + (1.0-theta)*dtau*(lo[j]*u[i-1]+di[j]*u[i]+up[j]*u[i+1])
+		}
+		next := make([]float64, m+1)
+		next[0], next[m] = lower(tau+dtau), upper(tau+dtau)
+		rhs[0] -= a[0] * next[0]
+		rhs[m-2] -= c[m-2] * next[m]
+		if exercise == nil {
+			x, err := SolveTridiagonal(a, d, c, rhs)
+			if err != nil {
+				return nil, err
+			}
+			copy(next[1:m], x)
+			return next, nil
+		}
+		copy(next[1:m], u[1:m])
+		for iter := 0; iter < cfg.MaxIterations; iter++ {
+			change := 0.0
+			for i := 1; i < m; i++ {
+				j := i - 1
+				y := rhs[j]
+				if j > 0 {
+					y -= a[j] * next[i-1]
+				}
+				if j < m-2 {
+					y -= c[j] * next[i+1]
+				}
+				y = Max(exercise[i], next[i]+cfg.Omega*(y/d[j]-next[i]))
+				change = Max(change, Abs(y-next[i]))
+				next[i] = y
+			}
+			if change <= cfg.Tolerance {
+				return next, nil
+			}
+		}
+		return nil, &analytical.InputError{Pricer: pricer, Field: "cfg.MaxIterations", Value: float64(cfg.MaxIterations), Reason: "must be large enough for PSOR to converge", Inputs: inputs}
+	}
+	values := make([][]float64, n+1)
+	values[n] = payoff
+	u := payoff
+	for i := 0; i < n; i++ {
+		tau := float64(i) * dt
+		var err error
+		if i < cfg.RannacherSteps {
+			if u, err = step(u, tau, dt/2.0, 1.0); err == nil {
+				u, err = step(u, tau+dt/2.0, dt/2.0, 1.0)
+			}
+		} else {
+			u, err = step(u, tau, dt, 0.5)
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[n-i-1] = u
+	}
+	times := make([]float64, n+1)
+	for i := range times {
+		times[i] = float64(i) * dt
+	}
+	return &Grid{spots, times, values}, nil
+}
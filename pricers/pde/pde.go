@@ -0,0 +1,232 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+/*
+Package pde provides the finite-difference pricers that can be used to value
+financial instruments and their risks by solving their partial differential
+equations.
+
+This is a multi-file package and is made up of the following source files:
+  pde.go            provides the common definitions that are used by the
+                    other source files in the package;
+  cranknicolson.go  provides the Crank-Nicolson pricers for the Generalized
+                    Black Scholes Merton partial differential equation.
+*/
+package pde
+
+import (
+	"fmt"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	. "math"
+)
+
+/*
+===============
+Types of Errors
+===============
+*/
+
+/*
+The error ErrInvalidConfig is returned when the grid settings are invalid.
+*/
+type ErrInvalidConfig string
+
+func (e ErrInvalidConfig) Error() string {
+	return fmt.Sprintf("%s", string(e))
+}
+
+/*
+==================
+Common Definitions
+==================
+*/
+
+/*
+ModelOutputs is the structure that holds the results returned by the pricing
+methods defined in the package. The greeks are read off the value grid;
+Theta is scaled by the same market conventions as in the analytical
+package.
+*/
+type ModelOutputs struct {
+	Value float64
+	Delta float64
+	Gamma float64
+	Theta float64
+
+	Conventions *analytical.Conventions // scaling of Theta; analytical.DefaultConventions() if nil
+}
+
+/*
+Config is the structure that holds the settings of a finite-difference
+grid. SpotMax is the upper edge of the grid of spot prices; when it is
+zero, it is set to five standard deviations above the spot price, so it
+should be set explicitly for a payoff that depends on spot prices further
+away. The first RannacherSteps time steps are each replaced by
+two fully implicit half steps to damp the oscillations caused by a
+non-smooth payoff. Omega, Tolerance and MaxIterations control the
+projected successive over-relaxation (PSOR) used for American exercise.
+
+Usage (example):
+var cfg = pde.DefaultConfig
+*/
+type Config struct {
+	SpotSteps      int
+	TimeSteps      int
+	RannacherSteps int
+	SpotMax        float64
+	Omega          float64
+	Tolerance      float64
+	MaxIterations  int
+}
+
+/*
+DefaultConfig holds the default settings of a finite-difference grid.
+*/
+var DefaultConfig = Config{
+	SpotSteps:      400,
+	TimeSteps:      400,
+	RannacherSteps: 2,
+	Omega:          1.2,
+	Tolerance:      1.0e-10,
+	MaxIterations:  10000,
+}
+
+/*
+Grid is the structure that holds the full grid of values computed by the
+pricing methods: Values[n][i] is the value of the option at time Times[n]
+from the valuation date (Times[0] is the valuation date and the last time
+is the expiry) and spot price Spots[i].
+*/
+type Grid struct {
+	Spots  []float64
+	Times  []float64
+	Values [][]float64
+}
+
+/*
+validate is an unexported method that returns the error ErrInvalidConfig
+if the grid settings are invalid; otherwise, it returns nil.
+*/
+func (cfg Config) validate() error {
+	switch {
+	case cfg.SpotSteps < 3:
+		return ErrInvalidConfig("The grid needs at least 3 spot steps.")
+	case cfg.TimeSteps < 1:
+		return ErrInvalidConfig("The grid needs at least 1 time step.")
+	case cfg.RannacherSteps < 0 || cfg.RannacherSteps > cfg.TimeSteps:
+		return ErrInvalidConfig("The number of Rannacher steps is out of range.")
+	case cfg.Omega <= 0.0 || cfg.Omega >= 2.0:
+		return ErrInvalidConfig("The PSOR relaxation parameter must lie in (0, 2).")
+	case cfg.Tolerance <= 0.0 || cfg.MaxIterations < 1:
+		return ErrInvalidConfig("The PSOR tolerance and iterations must be positive.")
+	}
+	return nil
+}
+
+/*
+checkInputs is an unexported function that returns an
+*analytical.InputError, with the name of the pricing method and its
+arguments in, if the spot price s is not positive, the time to expiry t or
+the volatility v is negative, or any of s, t, v, the risk-free rate r and
+the cost of carry b is not a finite number; otherwise, it returns nil.
+*/
+func checkInputs(pricer string, in map[string]float64) error {
+	for _, name := range []string{"s", "t", "v", "r", "b"} {
+		x := in[name]
+		reason := ""
+		switch {
+		case IsNaN(x) || IsInf(x, 0):
+			reason = "must be a finite number"
+		case name == "s" && x <= 0.0:
+			reason = "must be positive"
+		case (name == "t" || name == "v") && x < 0.0:
+			reason = "must not be negative"
+		}
+		if reason != "" {
+			return &analytical.InputError{Pricer: pricer, Field: name, Value: x, Reason: reason, Inputs: in}
+		}
+	}
+	return nil
+}
+
+/*
+Greeks reads off the value and greeks of the option at the spot price s on
+the valuation date by quadratic interpolation on the grid, and saves them in
+the fields of the ModelOutputs receiver. Theta is scaled by the market
+conventions of the receiver. It returns an *analytical.InputError if s
+lies outside the grid, an *analytical.OutputError if the value or a greek
+is not a finite number, or the error returned by
+analytical.Conventions.Scale if the conventions are invalid; otherwise, it
+returns nil.
+
+Usage (example):
+var out pde.ModelOutputs
+err := out.Greeks(grid, s)
+*/
+func (out *ModelOutputs) Greeks(g *Grid, s float64) error {
+	return out.greeks("Greeks", map[string]float64{"s": s}, g, s)
+}
+
+/*
+greeks is an unexported method that does the work of Greeks for the pricing
+method named pricer; the errors that it returns carry the name of the
+pricing method and its arguments inputs.
+*/
+func (out *ModelOutputs) greeks(pricer string, inputs map[string]float64, g *Grid, s float64) error {
+	m := len(g.Spots) - 1
+	if m < 2 || len(g.Times) < 2 || s < g.Spots[0] || s > g.Spots[m] {
+		return &analytical.InputError{Pricer: pricer, Field: "s", Value: s, Reason: "must lie on the grid", Inputs: inputs}
+	}
+	ds := g.Spots[1] - g.Spots[0]
+	// Interpolate around the interior node nearest to s.
+	j := int(Floor((s-g.Spots[0])/ds + 0.5))
+	j = int(Max(1.0, Min(float64(m-1), float64(j))))
+	x := (s - g.Spots[j]) / ds
+	quadratic := func(u []float64) (float64, float64, float64) {
+		d1 := (u[j+1] - u[j-1]) / 2.0
+		d2 := u[j+1] - 2.0*u[j] + u[j-1]
+		return u[j] + x*d1 + x*x*d2/2.0, (d1 + x*d2) / ds, d2 / (ds * ds)
+	}
+	value, delta, gamma := quadratic(g.Values[0])
+	next, _, _ := quadratic(g.Values[1])
+	out.Value, out.Delta, out.Gamma = value, delta, gamma
+	out.Theta = (next - value) / (g.Times[1] - g.Times[0])
+	for _, f := range []struct {
+		name  string
+		value float64
+	}{{"Value", out.Value}, {"Delta", out.Delta}, {"Gamma", out.Gamma}, {"Theta", out.Theta}} {
+		if IsNaN(f.value) || IsInf(f.value, 0) {
+			return &analytical.OutputError{Pricer: pricer, Field: f.name, Value: f.value, Inputs: inputs}
+		}
+	}
+	// Scaling Theta based on market conventions.
+	_, theta, _, err := out.Conventions.Scale(0.0, out.Theta, 0.0)
+	if err != nil {
+		return err
+	}
+	out.Theta = theta
+	return nil
+}
@@ -0,0 +1,226 @@
+/*
+******************************************************************************
+MIT License
+
+Copyright (c) 2016 Kervin Low
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+******************************************************************************
+*/
+
+package pde
+
+import (
+	"errors"
+	. "github.com/kervinlow/quantstruct/options"
+	"github.com/kervinlow/quantstruct/pricers/analytical"
+	"github.com/kervinlow/quantstruct/pricers/lattice"
+	. "math"
+	"testing"
+)
+
+/*
+refinedConfig is the grid settings on which the pricers are checked
+against the closed-form values of the analytical package.
+*/
+var refinedConfig = Config{
+	SpotSteps:      800,
+	TimeSteps:      800,
+	RannacherSteps: 4,
+	Omega:          1.2,
+	Tolerance:      1.0e-10,
+	MaxIterations:  10000,
+}
+
+func TestCN1947AgainstGBSM(t *testing.T) {
+	for _, ot := range []OptionType{Call, Put} {
+		for _, k := range []float64{80.0, 100.0, 120.0} {
+			var exact analytical.ModelOutputs
+			if err := exact.GBSM(ot, 100.0, k, 1.0, 0.3, 0.05, 0.02); err != nil {
+				t.Fatal(err)
+			}
+			var out ModelOutputs
+			if _, err := out.CN1947(refinedConfig, Vanilla{Type: ot, Strike: k}, European, 100.0, 1.0, 0.3, 0.05, 0.02); err != nil {
+				t.Fatal(err)
+			}
+			if Abs(out.Value-exact.Value) > 2.0e-3 || Abs(out.Delta-exact.Delta) > 1.0e-3 || Abs(out.Gamma-exact.Gamma) > 1.0e-4 {
+				t.Errorf("CN1947(%v, k=%v) = %.4f (delta %.4f, gamma %.5f), want %.4f (delta %.4f, gamma %.5f)",
+					ot, k, out.Value, out.Delta, out.Gamma, exact.Value, exact.Delta, exact.Gamma)
+			}
+		}
+	}
+}
+
+func TestCN1947BarrierAgainstRR1991(t *testing.T) {
+	cases := []struct {
+		ot OptionType
+		bt BarrierType
+		k  float64
+		h  float64
+	}{
+		{Call, DownAndOut, 100.0, 90.0},
+		{Call, DownAndIn, 100.0, 90.0},
+		{Call, UpAndOut, 100.0, 130.0},
+		{Call, UpAndIn, 100.0, 130.0},
+		{Put, DownAndOut, 100.0, 80.0},
+		{Put, DownAndIn, 100.0, 80.0},
+		{Put, UpAndOut, 100.0, 110.0},
+		{Put, UpAndIn, 100.0, 110.0},
+	}
+	for _, c := range cases {
+		var exact analytical.ModelOutputs
+		if err := exact.RR1991(c.ot, c.bt, 100.0, c.k, c.h, 2.0, 0.5, 0.25, 0.05, 0.02); err != nil {
+			t.Fatal(err)
+		}
+		var out ModelOutputs
+		if _, err := out.CN1947Barrier(refinedConfig, Vanilla{Type: c.ot, Strike: c.k}, c.bt, 100.0, c.h, 2.0, 0.5, 0.25, 0.05, 0.02); err != nil {
+			t.Fatal(err)
+		}
+		if Abs(out.Value-exact.Value) > 5.0e-3 {
+			t.Errorf("CN1947Barrier(%v, %v, h=%v) = %.4f, want %.4f", c.ot, c.bt, c.h, out.Value, exact.Value)
+		}
+	}
+}
+
+func TestCN1947BarrierInvalidType(t *testing.T) {
+	var out ModelOutputs
+	if _, err := out.CN1947Barrier(DefaultConfig, Vanilla{Type: Call, Strike: 100.0}, BarrierType(4), 100.0, 90.0, 0.0, 0.5, 0.25, 0.05, 0.02); err == nil {
+		t.Error("CN1947Barrier with an invalid barrier type returned no error")
+	}
+}
+
+func TestCN1947AmericanAgainstCRR1979(t *testing.T) {
+	cases := []struct {
+		ot OptionType
+		k  float64
+		b  float64
+	}{
+		{Put, 90.0, 0.05}, {Put, 100.0, 0.05}, {Put, 110.0, 0.05},
+		// With b < r, an American call may also be exercised early.
+		{Call, 90.0, -0.02}, {Call, 100.0, -0.02}, {Call, 110.0, -0.02},
+	}
+	for _, c := range cases {
+		var tree lattice.ModelOutputs
+		if err := tree.CRR1979(Vanilla{Type: c.ot, Strike: c.k}, American, 100.0, 1.0, 0.3, 0.05, c.b, nil, 2000); err != nil {
+			t.Fatal(err)
+		}
+		var out ModelOutputs
+		if _, err := out.CN1947(refinedConfig, Vanilla{Type: c.ot, Strike: c.k}, American, 100.0, 1.0, 0.3, 0.05, c.b); err != nil {
+			t.Fatal(err)
+		}
+		if Abs(out.Value-tree.Value) > 5.0e-3 {
+			t.Errorf("CN1947(American %v, k=%v) = %.4f, want %.4f", c.ot, c.k, out.Value, tree.Value)
+		}
+		// The American option is worth at least the European option.
+		var european ModelOutputs
+		if _, err := european.CN1947(refinedConfig, Vanilla{Type: c.ot, Strike: c.k}, European, 100.0, 1.0, 0.3, 0.05, c.b); err != nil {
+			t.Fatal(err)
+		}
+		if out.Value < european.Value {
+			t.Errorf("CN1947(American %v, k=%v) = %.4f, want at least the European %.4f", c.ot, c.k, out.Value, european.Value)
+		}
+	}
+}
+
+func TestCN1947InvalidInputs(t *testing.T) {
+	var out ModelOutputs
+	for _, es := range []ExerciseStyle{Bermudan, ExerciseStyle(42)} {
+		if _, err := out.CN1947(DefaultConfig, Vanilla{Type: Put, Strike: 100.0}, es, 100.0, 1.0, 0.3, 0.05, 0.05); err == nil {
+			t.Errorf("CN1947 with the exercise style %v returned no error", es)
+		} else if _, ok := err.(ErrInvalidConfig); !ok {
+			t.Errorf("CN1947 with the exercise style %v returned %v, want ErrInvalidConfig", es, err)
+		}
+	}
+	cases := []struct {
+		name       string
+		s, k, t, v float64
+		field      string
+	}{
+		{"negative spot", -100.0, 100.0, 1.0, 0.3, "s"},
+		{"negative time", 100.0, 100.0, -1.0, 0.3, "t"},
+		{"negative volatility", 100.0, 100.0, 1.0, -0.3, "v"},
+		{"NaN volatility", 100.0, 100.0, 1.0, NaN(), "v"},
+	}
+	for _, c := range cases {
+		_, err := out.CN1947(DefaultConfig, Vanilla{Type: Put, Strike: c.k}, American, c.s, c.t, c.v, 0.05, 0.05)
+		var e *analytical.InputError
+		if !errors.As(err, &e) || e.Pricer != "CN1947" || e.Field != c.field {
+			t.Errorf("CN1947 with a %s returned %v, want an *analytical.InputError for %s", c.name, err, c.field)
+		}
+	}
+	barriers := []struct {
+		name    string
+		s, h, x float64
+		field   string
+	}{
+		{"negative spot", -100.0, 90.0, 0.0, "s"},
+		{"zero barrier", 100.0, 0.0, 0.0, "h"},
+		{"NaN rebate", 100.0, 90.0, NaN(), "x"},
+	}
+	for _, c := range barriers {
+		_, err := out.CN1947Barrier(DefaultConfig, Vanilla{Type: Call, Strike: 100.0}, DownAndOut, c.s, c.h, c.x, 0.5, 0.25, 0.05, 0.02)
+		var e *analytical.InputError
+		if !errors.As(err, &e) || e.Pricer != "CN1947Barrier" || e.Field != c.field {
+			t.Errorf("CN1947Barrier with a %s returned %v, want an *analytical.InputError for %s", c.name, err, c.field)
+		}
+	}
+}
+
+func TestCN1947Conventions(t *testing.T) {
+	p := Vanilla{Type: Put, Strike: 100.0}
+	var perDay, perYear ModelOutputs
+	if _, err := perDay.CN1947(DefaultConfig, p, European, 100.0, 1.0, 0.3, 0.05, 0.02); err != nil {
+		t.Fatal(err)
+	}
+	perYear.Conventions = &analytical.Conventions{VolShift: analytical.PerUnit, DaysPerYear: analytical.PerYear, RateShift: analytical.PerUnit}
+	if _, err := perYear.CN1947(DefaultConfig, p, European, 100.0, 1.0, 0.3, 0.05, 0.02); err != nil {
+		t.Fatal(err)
+	}
+	if Abs(perYear.Theta-365.0*perDay.Theta) > 1.0e-9*Abs(perYear.Theta) {
+		t.Errorf("Theta per year = %v, want 365 times the Theta per day %v", perYear.Theta, perDay.Theta)
+	}
+	perYear.Conventions = &analytical.Conventions{}
+	if _, err := perYear.CN1947(DefaultConfig, p, European, 100.0, 1.0, 0.3, 0.05, 0.02); err == nil {
+		t.Error("CN1947 with invalid conventions returned no error")
+	}
+}
+
+func TestCN1947PSORNotConverged(t *testing.T) {
+	cfg := refinedConfig
+	cfg.MaxIterations = 1
+	var out ModelOutputs
+	_, err := out.CN1947(cfg, Vanilla{Type: Put, Strike: 100.0}, American, 100.0, 1.0, 0.3, 0.05, 0.02)
+	var e *analytical.InputError
+	if !errors.As(err, &e) || e.Pricer != "CN1947" || e.Field != "cfg.MaxIterations" {
+		t.Errorf("CN1947 with too few PSOR iterations returned %v, want an *analytical.InputError for cfg.MaxIterations", err)
+	}
+}
+
+func TestGreeksOutsideGrid(t *testing.T) {
+	var out ModelOutputs
+	grid, err := out.CN1947(DefaultConfig, Vanilla{Type: Call, Strike: 100.0}, European, 100.0, 1.0, 0.3, 0.05, 0.02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = out.Greeks(grid, 2.0*grid.Spots[len(grid.Spots)-1])
+	var e *analytical.InputError
+	if !errors.As(err, &e) || e.Field != "s" || !errors.Is(err, analytical.ErrInvalidInput) {
+		t.Errorf("Greeks outside the grid returned %v, want an *analytical.InputError for s", err)
+	}
+}